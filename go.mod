@@ -4,6 +4,7 @@ go 1.19
 
 require (
 	code.rocketnine.space/tslocum/cbind v0.1.5
+	github.com/andybalholm/brotli v1.1.1
 	github.com/atotto/clipboard v0.1.4
 	github.com/creack/pty v1.1.21
 	github.com/fsnotify/fsnotify v1.7.0
@@ -37,6 +37,9 @@ var (
 	// non match filter pattern.
 	nonMatchFilter string
 
+	// jsonFilter is the expression of the JSON filter.
+	jsonFilter string
+
 	// ver is version information.
 	ver bool
 	// helpKey is key bind information.
@@ -97,6 +100,9 @@ It supports various compressed files(gzip, bzip2, zstd, lz4, and xz).
 		// Set a global variable to convert to a style before opening the file.
 		oviewer.OverStrikeStyle = oviewer.ToTcellStyle(config.StyleOverStrike)
 		oviewer.OverLineStyle = oviewer.ToTcellStyle(config.StyleOverLine)
+		oviewer.JSONKeyStyle = oviewer.ToTcellStyle(config.StyleJSONKey)
+		oviewer.JSONStringStyle = oviewer.ToTcellStyle(config.StyleJSONString)
+		oviewer.JSONValueStyle = oviewer.ToTcellStyle(config.StyleJSONValue)
 		oviewer.MemoryLimit = config.MemoryLimit
 		oviewer.MemoryLimitFile = config.MemoryLimitFile
 		SetRedirect()
@@ -168,6 +174,9 @@ func RunOviewer(args []string) error {
 	if nonMatchFilter != "" {
 		ov.Filter(nonMatchFilter, true)
 	}
+	if jsonFilter != "" {
+		ov.JSONFilter(jsonFilter, false)
+	}
 
 	if err := ov.Run(); err != nil {
 		return err
@@ -345,7 +354,11 @@ func init() {
 	rootCmd.PersistentFlags().StringVarP(&pattern, "pattern", "", "", "search pattern")
 	rootCmd.PersistentFlags().StringVarP(&filter, "filter", "", "", "filter search pattern")
 	rootCmd.PersistentFlags().StringVarP(&nonMatchFilter, "non-match-filter", "", "", "filter non match search pattern")
+	rootCmd.PersistentFlags().StringVarP(&jsonFilter, "json-filter", "", "", "filter JSON lines by the expression")
 	rootCmd.PersistentFlags().BoolVarP(&oviewer.SkipExtract, "skip-extract", "", false, "skip extracting compressed files")
+	rootCmd.PersistentFlags().BoolVarP(&oviewer.IndexCache, "index-cache", "", false, "cache the line index of large files")
+	rootCmd.PersistentFlags().BoolVarP(&oviewer.MmapFile, "mmap", "", false, "map regular files into memory (truncated lines become errors)")
+	rootCmd.PersistentFlags().BoolVarP(&oviewer.Spool, "spool", "", false, "write the released chunks of non-seekable files to a temp file")
 
 	// Config.General
 	rootCmd.PersistentFlags().IntP("tab-width", "x", 8, "tab stop width")
@@ -375,6 +388,24 @@ func init() {
 	rootCmd.PersistentFlags().BoolP("column-width", "", false, "column mode for width")
 	_ = viper.BindPFlag("general.ColumnWidth", rootCmd.PersistentFlags().Lookup("column-width"))
 
+	rootCmd.PersistentFlags().BoolP("column-align", "", false, "align the columns separated by the delimiter")
+	_ = viper.BindPFlag("general.ColumnAlign", rootCmd.PersistentFlags().Lookup("column-align"))
+
+	rootCmd.PersistentFlags().BoolP("column-csv", "", false, "column mode that respects the quotes of CSV (within a line)")
+	_ = viper.BindPFlag("general.ColumnCSV", rootCmd.PersistentFlags().Lookup("column-csv"))
+
+	rootCmd.PersistentFlags().BoolP("column-auto", "", false, "detect the column delimiter and the header")
+	_ = viper.BindPFlag("general.ColumnAuto", rootCmd.PersistentFlags().Lookup("column-auto"))
+
+	rootCmd.PersistentFlags().IntSliceP("column-order", "", nil, "column numbers to display in order")
+	_ = viper.BindPFlag("general.ColumnOrder", rootCmd.PersistentFlags().Lookup("column-order"))
+
+	rootCmd.PersistentFlags().IntP("column-pin", "", 0, "number of columns pinned to the left")
+	_ = viper.BindPFlag("general.ColumnPin", rootCmd.PersistentFlags().Lookup("column-pin"))
+
+	rootCmd.PersistentFlags().BoolP("column-logfmt", "", false, "column mode for logfmt")
+	_ = viper.BindPFlag("general.ColumnLogfmt", rootCmd.PersistentFlags().Lookup("column-logfmt"))
+
 	rootCmd.PersistentFlags().BoolP("column-rainbow", "", false, "column mode to rainbow")
 	_ = viper.BindPFlag("general.ColumnRainbow", rootCmd.PersistentFlags().Lookup("column-rainbow"))
 
@@ -387,6 +418,9 @@ func init() {
 	rootCmd.PersistentFlags().BoolP("plain", "p", false, "disable original decoration")
 	_ = viper.BindPFlag("general.PlainMode", rootCmd.PersistentFlags().Lookup("plain"))
 
+	rootCmd.PersistentFlags().BoolP("json-mode", "", false, "display JSON lines indented (in wrap mode)")
+	_ = viper.BindPFlag("general.JSONMode", rootCmd.PersistentFlags().Lookup("json-mode"))
+
 	rootCmd.PersistentFlags().StringP("column-delimiter", "d", ",", "column delimiter `character`")
 	_ = viper.BindPFlag("general.ColumnDelimiter", rootCmd.PersistentFlags().Lookup("column-delimiter"))
 	_ = rootCmd.RegisterFlagCompletionFunc("column-delimiter", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
@@ -454,6 +488,15 @@ func init() {
 	rootCmd.PersistentFlags().BoolP("regexp-search", "", false, "regular expression search")
 	_ = viper.BindPFlag("RegexpSearch", rootCmd.PersistentFlags().Lookup("regexp-search"))
 
+	rootCmd.PersistentFlags().BoolP("boolean-search", "", false, "search with &(AND), |(OR) and !(NOT)")
+	_ = viper.BindPFlag("BooleanSearch", rootCmd.PersistentFlags().Lookup("boolean-search"))
+
+	rootCmd.PersistentFlags().BoolP("fuzzy-search", "", false, "fuzzy search that matches the characters in order")
+	_ = viper.BindPFlag("FuzzySearch", rootCmd.PersistentFlags().Lookup("fuzzy-search"))
+
+	rootCmd.PersistentFlags().BoolP("column-search", "", false, "search only in the column of the cursor in column mode")
+	_ = viper.BindPFlag("ColumnSearch", rootCmd.PersistentFlags().Lookup("column-search"))
+
 	rootCmd.PersistentFlags().BoolP("incsearch", "", true, "incremental search")
 	_ = viper.BindPFlag("Incsearch", rootCmd.PersistentFlags().Lookup("incsearch"))
 
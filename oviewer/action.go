@@ -28,7 +28,11 @@ func (root *Root) toggleWrapMode() {
 	if x < m.x || x > m.x+(root.scr.vWidth-root.scr.startX) {
 		m.x = x
 	}
-	root.setMessagef("Set WrapMode %t", m.WrapMode)
+	// The contents of JSON mode depend on wrap mode.
+	if m.JSONMode {
+		m.ClearCache()
+	}
+	root.setMessagef("Set WrapMode %t%s", m.WrapMode, m.jsonModeNote())
 }
 
 // toggleColumnMode toggles ColumnMode each time it is called.
@@ -38,6 +42,7 @@ func (root *Root) toggleColumnMode() {
 	if root.Doc.ColumnMode {
 		root.Doc.columnCursor = root.Doc.optimalCursor(root.Doc.columnCursor)
 	}
+	root.Doc.clearOrderCache()
 	root.setMessagef("Set ColumnMode %t", root.Doc.ColumnMode)
 }
 
@@ -49,11 +54,76 @@ func (root *Root) toggleColumnWidth() {
 	} else {
 		root.Doc.ColumnWidth = true
 		root.Doc.ColumnMode = true
+		root.Doc.ColumnCSV = false
+	}
+	if root.Doc.ColumnLogfmt || root.Doc.ColumnAlign {
+		root.Doc.ColumnLogfmt = false
+		root.Doc.ColumnAlign = false
+		root.Doc.ClearCache()
 	}
-	root.Doc.columnWidths = nil
+	root.Doc.resetColumnWidths()
+	root.Doc.clearOrderCache()
 	root.setMessagef("Set ColumnWidth %t", root.Doc.ColumnWidth)
 }
 
+// toggleColumnLogfmt toggles ColumnLogfmt each time it is called.
+func (root *Root) toggleColumnLogfmt() {
+	m := root.Doc
+	if m.ColumnLogfmt {
+		m.ColumnLogfmt = false
+		m.ColumnMode = false
+	} else {
+		m.ColumnLogfmt = true
+		m.ColumnMode = true
+		m.ColumnWidth = false
+		m.ColumnCSV = false
+		m.ColumnAlign = false
+	}
+	m.logfmtKeys = nil
+	m.logfmtWidths = nil
+	m.columnCursor = 0
+	m.x = 0
+	m.ClearCache()
+	root.setMessagef("Set ColumnLogfmt %t", m.ColumnLogfmt)
+}
+
+// toggleColumnCSV toggles ColumnCSV each time it is called.
+// ColumnCSV separates the columns by the delimiter,
+// so it cannot be used together with ColumnWidth and ColumnLogfmt.
+func (root *Root) toggleColumnCSV() {
+	m := root.Doc
+	m.ColumnCSV = !m.ColumnCSV
+	if m.ColumnCSV {
+		m.ColumnMode = true
+		m.ColumnWidth = false
+		if m.ColumnLogfmt {
+			m.ColumnLogfmt = false
+			m.ClearCache()
+		}
+		m.columnCursor = m.optimalCursor(m.columnCursor)
+	}
+	m.clearAlignWidths()
+	m.clearOrderCache()
+	root.setMessagef("Set ColumnCSV %t", m.ColumnCSV)
+}
+
+// toggleColumnAlign toggles ColumnAlign each time it is called.
+// ColumnAlign aligns the columns separated by the delimiter,
+// so it cannot be used together with ColumnWidth and ColumnLogfmt.
+func (root *Root) toggleColumnAlign() {
+	m := root.Doc
+	m.ColumnAlign = !m.ColumnAlign
+	if m.ColumnAlign {
+		m.ColumnMode = true
+		m.ColumnWidth = false
+		m.ColumnLogfmt = false
+	}
+	m.resetAlignWidths()
+	m.x = 0
+	m.ClearCache()
+	root.setMessagef("Set ColumnAlign %t", m.ColumnAlign)
+}
+
 // toggleAlternateRows toggles the AlternateRows each time it is called.
 func (root *Root) toggleAlternateRows() {
 	root.Doc.AlternateRows = !root.Doc.AlternateRows
@@ -73,6 +143,23 @@ func (root *Root) togglePlain() {
 	root.setMessagef("Set PlainMode %t", root.Doc.PlainMode)
 }
 
+// toggleJSONMode toggles JSON mode.
+func (root *Root) toggleJSONMode() {
+	m := root.Doc
+	m.JSONMode = !m.JSONMode
+	m.topLX = 0
+	m.ClearCache()
+	root.setMessagef("Set JSONMode %t%s", m.JSONMode, m.jsonModeNote())
+}
+
+// jsonModeNote returns a note that JSON mode is not effective without wrap mode.
+func (m *Document) jsonModeNote() string {
+	if m.JSONMode && !m.WrapMode {
+		return " (JSON mode requires wrap mode)"
+	}
+	return ""
+}
+
 // togglePlain toggles column rainbow mode.
 func (root *Root) toggleRainbow() {
 	root.Doc.ColumnRainbow = !root.Doc.ColumnRainbow
@@ -172,6 +259,7 @@ func (root *Root) watchControl() {
 // Jump by section if JumpTargetSection is true.
 func (root *Root) searchGo(lN int) {
 	root.resetSelect()
+	root.Doc.setMatchCurrent(root.searcher, lN)
 	x := root.searchXPos(lN)
 	if root.Doc.jumpTargetSection {
 		root.Doc.searchGoSection(lN, x)
@@ -300,7 +388,11 @@ func (root *Root) setHeader(input string) {
 	}
 
 	root.Doc.Header = num
-	root.Doc.columnWidths = nil
+	root.Doc.resetColumnWidths()
+	if root.Doc.ColumnLogfmt {
+		root.Doc.logfmtKeys = nil
+		root.Doc.ClearCache()
+	}
 	root.setMessagef("Set header lines %d", num)
 }
 
@@ -320,6 +412,7 @@ func (root *Root) setSkipLines(input string) {
 	}
 
 	root.Doc.SkipLines = num
+	root.Doc.clearAlignWidths()
 	root.setMessagef("Set skip lines %d", num)
 }
 
@@ -399,11 +492,36 @@ func (root *Root) setViewMode(modeName string) {
 
 	root.Doc.general = mergeGeneral(root.Doc.general, c)
 	root.Doc.regexpCompile()
+	root.Doc.resetAlignWidths()
 	root.Doc.ClearCache()
 	root.ViewSync()
 	root.setMessagef("Set mode %s", modeName)
 }
 
+// setColumnKey moves the column cursor to the logfmt column of the key.
+func (root *Root) setColumnKey(input string) {
+	m := root.Doc
+	if !m.ColumnLogfmt {
+		root.setMessage("Set column key: not logfmt column mode")
+		return
+	}
+	c := logfmtColumn(m.logfmtKeys, input)
+	if c < 0 {
+		root.setMessagef("Set column key %s: %s", input, ErrNoColumn.Error())
+		return
+	}
+	x, err := m.optimalX(c)
+	if err != nil {
+		root.setMessageLog(err.Error())
+		return
+	}
+	m.columnCursor = c
+	if !m.WrapMode {
+		m.x = x
+	}
+	root.setMessagef("Set column key %s", input)
+}
+
 // setDelimiter sets the delimiter string.
 func (root *Root) setDelimiter(input string) {
 	root.Doc.setDelimiter(input)
@@ -423,6 +541,7 @@ func (root *Root) setTabWidth(input string) {
 
 	root.Doc.TabWidth = width
 	root.setMessagef("Set tab width %d", width)
+	root.Doc.resetAlignWidths()
 	root.Doc.ClearCache()
 }
 
@@ -602,6 +721,7 @@ func calculatePosition(length int, str string) float64 {
 // ViewSync redraws the whole thing.
 func (root *Root) ViewSync() {
 	root.resetSelect()
+	root.columnAutoDetect()
 	root.prepareStartX()
 	root.prepareView()
 	root.Screen.Sync()
@@ -640,6 +760,7 @@ func (root *Root) prepareStartX() {
 // updateEndNum updates the last line number.
 func (root *Root) updateEndNum() {
 	root.debugMessage(fmt.Sprintf("Update EndNum:%d", root.Doc.BufEndNum()))
+	root.columnAutoDetect()
 	root.prepareStartX()
 	root.drawStatus()
 	root.Screen.Sync()
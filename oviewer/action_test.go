@@ -40,6 +40,42 @@ func TestRoot_toggleColumnMode(t *testing.T) {
 	}
 }
 
+func TestRoot_toggleJSONMode(t *testing.T) {
+	tests := []struct {
+		name     string
+		wrapMode bool
+		want     string
+	}{
+		{
+			name:     "testWrap",
+			wrapMode: true,
+			want:     "Set JSONMode true",
+		},
+		{
+			name:     "testNoWrap",
+			wrapMode: false,
+			want:     "Set JSONMode true (JSON mode requires wrap mode)",
+		},
+	}
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := NewRoot(bytes.NewBufferString("test"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			root.Doc.WrapMode = tt.wrapMode
+			root.toggleJSONMode()
+			if root.message != tt.want {
+				t.Errorf("root.toggleJSONMode() message = %q, want %q", root.message, tt.want)
+			}
+		})
+	}
+}
+
 func Test_rangeBA(t *testing.T) {
 	type args struct {
 		str string
@@ -0,0 +1,308 @@
+package oviewer
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// archive represents the type of archive.
+type archive int
+
+const (
+	noArchive archive = iota
+	zipArchive
+	tarArchive
+)
+
+// tarHeaderSize is the size of the tar header block.
+const tarHeaderSize = 512
+
+// archiveType returns the archive format of the file.
+// Tar is detected after decompressing with the compressed format.
+func archiveType(f *os.File) (archive, Compressed) {
+	header := make([]byte, tarHeaderSize)
+	n, _ := io.ReadFull(io.NewSectionReader(f, 0, tarHeaderSize), header)
+	header = header[:n]
+	if bytes.HasPrefix(header, []byte("PK\x03\x04")) || bytes.HasPrefix(header, []byte("PK\x05\x06")) {
+		return zipArchive, UNCOMPRESSED
+	}
+	if len(header) < 7 {
+		return noArchive, UNCOMPRESSED
+	}
+
+	cFormat := compressTypeByName(f.Name())
+	if cFormat == UNCOMPRESSED {
+		cFormat = compressType(header)
+	}
+	if cFormat != UNCOMPRESSED {
+		r := compressedFormatReader(cFormat, io.NewSectionReader(f, 0, 1<<63-1))
+		header = make([]byte, tarHeaderSize)
+		if _, err := io.ReadFull(r, header); err != nil {
+			return noArchive, cFormat
+		}
+	}
+	if isTarHeader(header) {
+		return tarArchive, cFormat
+	}
+	return noArchive, cFormat
+}
+
+// isTarHeader returns true if the block is a ustar (POSIX or GNU) header.
+func isTarHeader(header []byte) bool {
+	if len(header) < tarHeaderSize {
+		return false
+	}
+	return bytes.HasPrefix(header[257:], []byte("ustar"))
+}
+
+// archiveFile is the archive file shared by the documents of the members.
+// The file is closed when the documents of all members are closed.
+type archiveFile struct {
+	file *os.File
+	refs int32
+}
+
+// release closes the file if it is the last reference.
+func (a *archiveFile) release() {
+	if atomic.AddInt32(&a.refs, -1) == 0 {
+		if err := a.file.Close(); err != nil {
+			log.Printf("close %s: %s", a.file.Name(), err)
+		}
+	}
+}
+
+// archiveMember is the Source of a member of the archive.
+type archiveMember struct {
+	Source
+	archive *archiveFile
+	once    sync.Once
+}
+
+// Close closes the member and releases the archive file.
+func (s *archiveMember) Close() error {
+	var err error
+	if c, ok := s.Source.(io.Closer); ok {
+		err = c.Close()
+	}
+	s.once.Do(s.archive.release)
+	return err
+}
+
+// OpenArchive opens each regular file in the zip or tar archive as a Document.
+// The FileName of the Document is "archive:path/inside".
+// Tar can be compressed in any of the supported compressed formats.
+// ErrNotArchive is returned if the file is not an archive or SkipExtract is set.
+func OpenArchive(fileName string) ([]*Document, error) {
+	if SkipExtract {
+		return nil, ErrNotArchive
+	}
+	fi, err := os.Stat(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("%s %w", fileName, ErrNotFound)
+	}
+	if !fi.Mode().IsRegular() {
+		return nil, ErrNotArchive
+	}
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	archive := &archiveFile{file: f}
+	var sources []archiveSource
+	aFormat, cFormat := archiveType(f)
+	switch aFormat {
+	case zipArchive:
+		sources, err = zipSources(fileName, f, fi.Size())
+	case tarArchive:
+		sources, err = tarSources(fileName, f, cFormat)
+	default:
+		f.Close()
+		return nil, ErrNotArchive
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if len(sources) == 0 {
+		f.Close()
+		return nil, fmt.Errorf("%s: no files in archive %w", fileName, ErrNotFound)
+	}
+
+	return archiveDocuments(archive, sources)
+}
+
+// archiveDocuments returns the documents of the members of the archive.
+// If a member cannot be opened, the documents already created and the archive file are closed.
+func archiveDocuments(archive *archiveFile, sources []archiveSource) ([]*Document, error) {
+	archive.refs = int32(len(sources))
+	members := make([]*archiveMember, len(sources))
+	for n, as := range sources {
+		members[n] = &archiveMember{Source: as.source, archive: archive}
+	}
+	docs := make([]*Document, 0, len(sources))
+	for n, as := range sources {
+		m, err := newArchiveDocument(as.name, members[n])
+		if err != nil {
+			for _, doc := range docs {
+				doc.requestClose()
+			}
+			// Release the references of the members without documents.
+			for _, member := range members[len(docs):] {
+				member.Close()
+			}
+			return nil, fmt.Errorf("%s: %w", as.name, err)
+		}
+		docs = append(docs, m)
+	}
+	return docs, nil
+}
+
+// archiveSource is the name and the Source of a member of the archive.
+type archiveSource struct {
+	name   string
+	source Source
+}
+
+// zipSources returns the sources of the files in the zip archive.
+// Each member is decompressed from the archive file when it is read.
+func zipSources(fileName string, f *os.File, size int64) ([]archiveSource, error) {
+	zr, err := zip.NewReader(f, size)
+	if err != nil {
+		return nil, fmt.Errorf("zip: %w", err)
+	}
+	var sources []archiveSource
+	for _, zf := range zr.File {
+		if !zf.Mode().IsRegular() {
+			continue
+		}
+		zf := zf
+		sources = append(sources, archiveSource{
+			name: fileName + ":" + zf.Name,
+			source: NewReaderSource(func() (io.Reader, error) {
+				return zf.Open()
+			}),
+		})
+	}
+	return sources, nil
+}
+
+// tarSources returns the sources of the regular files in the tar archive.
+// An uncompressed member of an uncompressed tar is read from its section of the file.
+// A member of a compressed tar is read by decompressing the archive
+// from the beginning to the member each time it is opened,
+// because the compressed archive can only be read sequentially.
+// The members are listed by reading the whole archive once, but the contents are not kept.
+func tarSources(fileName string, f *os.File, cFormat Compressed) ([]archiveSource, error) {
+	var r io.Reader = io.NewSectionReader(f, 0, 1<<63-1)
+	if cFormat != UNCOMPRESSED {
+		r = compressedFormatReader(cFormat, r)
+	}
+	tr := tar.NewReader(r)
+	var sources []archiveSource
+	for index := 0; ; index++ {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("tar: %w", err)
+		}
+		if !hdr.FileInfo().Mode().IsRegular() {
+			continue
+		}
+
+		name := fileName + ":" + hdr.Name
+		var src Source
+		if cFormat == UNCOMPRESSED {
+			// The tar reader seeks the section, so the current position is the start of the contents.
+			offset, err := r.(io.Seeker).Seek(0, io.SeekCurrent)
+			if err != nil {
+				return nil, err
+			}
+			src = tarSectionSource(name, io.NewSectionReader(f, offset, hdr.Size))
+		} else {
+			index := index
+			src = NewReaderSource(func() (io.Reader, error) {
+				return tarMemberReader(f, cFormat, index)
+			})
+		}
+		sources = append(sources, archiveSource{name: name, source: src})
+	}
+	return sources, nil
+}
+
+// tarSectionSource returns the Source of the section of the uncompressed tar.
+// The section is seekable if the member itself is not compressed.
+func tarSectionSource(name string, section *io.SectionReader) Source {
+	head := make([]byte, 7)
+	n, _ := io.ReadFull(io.NewSectionReader(section, 0, int64(len(head))), head)
+	if compressTypeByName(name) == UNCOMPRESSED && compressType(head[:n]) == UNCOMPRESSED {
+		return NewReaderAtSource(section, section.Size())
+	}
+	return NewReaderSource(func() (io.Reader, error) {
+		return io.NewSectionReader(section, 0, section.Size()), nil
+	})
+}
+
+// tarMemberReader returns the reader of the member at the index of the compressed tar.
+func tarMemberReader(f *os.File, cFormat Compressed, index int) (io.Reader, error) {
+	tr := tar.NewReader(compressedFormatReader(cFormat, io.NewSectionReader(f, 0, 1<<63-1)))
+	for i := 0; i <= index; i++ {
+		if _, err := tr.Next(); err != nil {
+			return nil, fmt.Errorf("tar: %w", err)
+		}
+	}
+	return tr, nil
+}
+
+// newArchiveDocument returns a Document that reads the member of the archive.
+// A compressed member is also uncompressed.
+func newArchiveDocument(name string, src *archiveMember) (*Document, error) {
+	m, err := NewDocument()
+	if err != nil {
+		return nil, err
+	}
+	m.FileName = name
+	if !src.Seekable() {
+		src.Source = &uncompressedSource{Source: src.Source, name: name, doc: m}
+	}
+	if err := m.ControlSource(src); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// uncompressedSource is a non-seekable Source that uncompresses the compressed member.
+type uncompressedSource struct {
+	Source
+	name string
+	doc  *Document
+}
+
+// Open returns the uncompressed reader of the member.
+func (s *uncompressedSource) Open() (io.Reader, error) {
+	r, err := s.Source.Open()
+	if err != nil {
+		return nil, err
+	}
+	cFormat, r := uncompressedReader(s.name, r, false)
+	s.doc.CFormat = cFormat
+	return r, nil
+}
+
+// Close closes the member.
+func (s *uncompressedSource) Close() error {
+	if c, ok := s.Source.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package oviewer
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+var archiveFiles = []struct {
+	name string
+	body string
+}{
+	{name: "a.log", body: "a1\na2\n"},
+	{name: "dir/b.log", body: "b1\nb2\nb3\n"},
+	{name: "c.log.gz", body: "c1\n"},
+}
+
+// archiveBody returns the body of the member, compressed if the name ends with .gz.
+func archiveBody(t *testing.T, name string, body string) []byte {
+	t.Helper()
+	if filepath.Ext(name) != ".gz" {
+		return []byte(body)
+	}
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write([]byte(body)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func tarArchiveData(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	if err := tw.WriteHeader(&tar.Header{Name: "dir/", Typeflag: tar.TypeDir, Mode: 0o755}); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range archiveFiles {
+		body := archiveBody(t, f.name, f.body)
+		if err := tw.WriteHeader(&tar.Header{Name: f.name, Mode: 0o644, Size: int64(len(body))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func zipArchiveData(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range archiveFiles {
+		w, err := zw.Create(f.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(archiveBody(t, f.name, f.body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestOpenArchive(t *testing.T) {
+	tests := []struct {
+		name     string
+		fileName string
+		data     func(t *testing.T) []byte
+		wantErr  error
+	}{
+		{
+			name:     "testTar",
+			fileName: "test.tar",
+			data:     tarArchiveData,
+		},
+		{
+			name:     "testTarGz",
+			fileName: "test.tar.gz",
+			data: func(t *testing.T) []byte {
+				return archiveBody(t, "test.tar.gz", string(tarArchiveData(t)))
+			},
+		},
+		{
+			name:     "testZip",
+			fileName: "test.zip",
+			data:     zipArchiveData,
+		},
+		{
+			name:     "testNotArchive",
+			fileName: "test.txt",
+			data: func(t *testing.T) []byte {
+				return []byte("test\n")
+			},
+			wantErr: ErrNotArchive,
+		},
+		{
+			name:     "testGzNotArchive",
+			fileName: "test.txt.gz",
+			data: func(t *testing.T) []byte {
+				return archiveBody(t, "test.txt.gz", "test\n")
+			},
+			wantErr: ErrNotArchive,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileName := filepath.Join(t.TempDir(), tt.fileName)
+			if err := os.WriteFile(fileName, tt.data(t), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			docs, err := OpenArchive(fileName)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("OpenArchive() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(docs) != len(archiveFiles) {
+				t.Fatalf("OpenArchive() = %d documents, want %d", len(docs), len(archiveFiles))
+			}
+			for i, m := range docs {
+				f := archiveFiles[i]
+				if want := fileName + ":" + f.name; m.FileName != want {
+					t.Errorf("FileName = %v, want %v", m.FileName, want)
+				}
+				for !m.BufEOF() {
+				}
+				lines := bytes.Split([]byte(f.body), []byte("\n"))
+				if m.BufEndNum() != len(lines)-1 {
+					t.Errorf("%s BufEndNum() = %v, want %v", f.name, m.BufEndNum(), len(lines)-1)
+				}
+				if got := m.LineString(0); got != string(lines[0]) {
+					t.Errorf("%s LineString(0) = %v, want %v", f.name, got, string(lines[0]))
+				}
+			}
+		})
+	}
+}
+
+func TestOpenArchive_close(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "test.tar.gz")
+	if err := os.WriteFile(fileName, archiveBody(t, "test.tar.gz", string(tarArchiveData(t))), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	docs, err := OpenArchive(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive := docs[0].source.(*archiveMember).archive
+	for n, m := range docs {
+		for !m.BufEOF() {
+		}
+		if !m.requestClose() {
+			t.Fatalf("requestClose() = false")
+		}
+		if got, want := atomic.LoadInt32(&archive.refs), int32(len(docs)-n-1); got != want {
+			t.Errorf("refs = %d, want %d", got, want)
+		}
+	}
+	if _, err := archive.file.Stat(); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("archive file is not closed: %v", err)
+	}
+}
+
+func TestOpenArchive_skipExtract(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "test.tar")
+	if err := os.WriteFile(fileName, tarArchiveData(t), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	SkipExtract = true
+	defer func() { SkipExtract = false }()
+	if _, err := OpenArchive(fileName); !errors.Is(err, ErrNotArchive) {
+		t.Errorf("OpenArchive() error = %v, want %v", err, ErrNotArchive)
+	}
+}
+
+func TestArchiveDocuments_corrupt(t *testing.T) {
+	data := zipArchiveData(t)
+	// Corrupt the local header of the second member.
+	first := bytes.Index(data, []byte("PK\x03\x04"))
+	second := first + 4 + bytes.Index(data[first+4:], []byte("PK\x03\x04"))
+	data[second] = 'X'
+	fileName := filepath.Join(t.TempDir(), "test.zip")
+	if err := os.WriteFile(fileName, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources, err := zipSources(fileName, f, int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive := &archiveFile{file: f}
+	if _, err := archiveDocuments(archive, sources); !errors.Is(err, zip.ErrFormat) {
+		t.Fatalf("archiveDocuments() error = %v, want %v", err, zip.ErrFormat)
+	}
+	if got := atomic.LoadInt32(&archive.refs); got != 0 {
+		t.Errorf("refs = %d, want 0", got)
+	}
+	if _, err := f.Stat(); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("archive file is not closed: %v", err)
+	}
+	if _, err := OpenArchive(fileName); !errors.Is(err, zip.ErrFormat) {
+		t.Errorf("OpenArchive() error = %v, want %v", err, zip.ErrFormat)
+	}
+}
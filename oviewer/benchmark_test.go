@@ -3,6 +3,7 @@ package oviewer
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -31,6 +32,29 @@ func Parse_Helper(b *testing.B, fileName string) {
 	}
 }
 
+func BenchmarkStripEscapeSequence_AnsiEscape(b *testing.B) {
+	Strip_Helper(b, filepath.Join(testdata, "ansiescape.txt"))
+}
+
+func BenchmarkStripEscapeSequence_ChromaTerm(b *testing.B) {
+	Strip_Helper(b, filepath.Join(testdata, "ct.log"))
+}
+
+func Strip_Helper(b *testing.B, fileName string) {
+	b.Helper()
+	f, err := os.ReadFile(fileName)
+	if err != nil {
+		b.Fatal(err)
+	}
+	lines := strings.Split(string(f), "\n")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, line := range lines {
+			stripEscapeSequenceString(line)
+		}
+	}
+}
+
 func BenchmarkDraw_Normal(b *testing.B) {
 	Draw_Helper(b, filepath.Join(testdata, "normal.txt"))
 }
@@ -0,0 +1,151 @@
+package oviewer
+
+// alignPadding is the blank of the aligned columns.
+// It has no character, so it is not included in the string of the line
+// and the search and copy work on the original text.
+var alignPadding = content{
+	mainc: 0,
+	combc: nil,
+	width: 1,
+	style: DefaultContent.style,
+}
+
+// alignSampleLines is the number of lines to compute the widths of the aligned columns.
+const alignSampleLines = 1000
+
+// alignColumns returns the columns of the contents with the delimiter that follows each column.
+// It returns nil if the contents have no delimiter.
+// The contents are parsed with the tab width 1, and the tabs are expanded in each column.
+func (m *Document) alignColumns(lc contents, tabWidth int) []contents {
+	s, pos := ContentsToStr(lc)
+	indexes := m.delimiterIndex(s)
+	if len(indexes) == 0 {
+		return nil
+	}
+	columns := make([]contents, 0, len(indexes)+1)
+	start := 0
+	for _, idx := range indexes {
+		end := pos.x(idx[1])
+		columns = append(columns, expandTabs(lc[start:end], tabWidth))
+		start = end
+	}
+	return append(columns, expandTabs(lc[start:], tabWidth))
+}
+
+// expandTabs expands the tabs of the contents parsed with the tab width 1,
+// as parseString does with tabWidth.
+func expandTabs(lc contents, tabWidth int) contents {
+	if tabWidth == 1 {
+		return lc
+	}
+	tabs := 0
+	for _, c := range lc {
+		if c.mainc == '\t' {
+			tabs++
+		}
+	}
+	if tabs == 0 {
+		return lc
+	}
+	expanded := make(contents, 0, len(lc)+tabs*max(tabWidth, 2))
+	tabx := 0
+	for _, c := range lc {
+		if c.mainc != '\t' {
+			expanded = append(expanded, c)
+			tabx += c.width
+			continue
+		}
+		switch {
+		case tabWidth > 0:
+			tabStop := tabWidth - (tabx % tabWidth)
+			expanded = append(expanded, c)
+			c.mainc = 0
+			for i := 0; i < tabStop-1; i++ {
+				expanded = append(expanded, c)
+			}
+			tabx += tabStop
+		case tabWidth < 0:
+			c.style = c.style.Reverse(true)
+			c.mainc = '\\'
+			expanded = append(expanded, c)
+			c.mainc = 't'
+			expanded = append(expanded, c)
+			tabx += 2
+		}
+	}
+	return expanded
+}
+
+// needAlignWidths returns true if the widths of the aligned columns are not computed yet,
+// or were computed from fewer lines than the sample and more lines have been loaded.
+func (m *Document) needAlignWidths() bool {
+	if !m.alignComputed {
+		return true
+	}
+	return m.alignWidthsEnd < m.SkipLines+alignSampleLines && m.BufEndNum() > m.alignWidthsEnd
+}
+
+// setAlignWidths sets the widths of the aligned columns.
+// The widths are the maximum widths of the columns in the first 1000 lines (maximum),
+// including the header.
+func (m *Document) setAlignWidths() {
+	end := min(m.SkipLines+alignSampleLines, m.BufEndNum())
+	var widths []int
+	for _, str := range m.sampleLines(m.SkipLines, end) {
+		lc := parseString(str, 1)
+		if m.columnOrdered() {
+			lc = m.orderContents(lc)
+		}
+		for c, column := range m.alignColumns(lc, m.TabWidth) {
+			if c < len(widths) {
+				widths[c] = max(widths[c], len(column))
+				continue
+			}
+			widths = append(widths, len(column))
+		}
+	}
+	m.alignComputed = true
+	m.alignWidthsEnd = end
+	m.alignWidths = widths
+	m.ClearCache()
+}
+
+// resetAlignWidths discards the widths of the aligned columns to compute them again.
+func (m *Document) resetAlignWidths() {
+	m.alignWidths = nil
+	m.alignComputed = false
+}
+
+// clearAlignWidths clears the widths of the aligned columns to set them again.
+func (m *Document) clearAlignWidths() {
+	if !m.ColumnAlign {
+		return
+	}
+	m.resetAlignWidths()
+	m.ClearCache()
+}
+
+// alignContents returns the contents with the columns padded to the widths.
+// It returns false if the contents have no delimiter.
+func (m *Document) alignContents(lc contents, tabWidth int) (contents, bool) {
+	if len(m.alignWidths) == 0 {
+		return nil, false
+	}
+	columns := m.alignColumns(lc, tabWidth)
+	if len(columns) == 0 {
+		return nil, false
+	}
+
+	alc := make(contents, 0, len(lc))
+	for c, column := range columns {
+		alc = append(alc, column...)
+		if c == len(columns)-1 || c >= len(m.alignWidths) {
+			continue
+		}
+		// One blank between the columns.
+		for w := len(column); w < m.alignWidths[c]+1; w++ {
+			alc = append(alc, alignPadding)
+		}
+	}
+	return alc, true
+}
@@ -0,0 +1,177 @@
+package oviewer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestDocument_alignColumns(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		str      string
+		tabWidth int
+		want     []string
+	}{
+		{
+			name:     "testComma",
+			str:      "a,bb,c",
+			tabWidth: 8,
+			want:     []string{"a,", "bb,", "c"},
+		},
+		{
+			name:     "testFence",
+			str:      "|a|b|",
+			tabWidth: 8,
+			want:     []string{"|", "a|", "b|", ""},
+		},
+		{
+			name:     "testEscape",
+			str:      "\x1b[31mred\x1b[m,b",
+			tabWidth: 8,
+			want:     []string{"red,", "b"},
+		},
+		{
+			name:     "testTab",
+			str:      "a,b\tc",
+			tabWidth: 4,
+			want:     []string{"a,", "b\tc"},
+		},
+		{
+			name:     "testNoDelimiter",
+			str:      "abc",
+			tabWidth: 8,
+			want:     nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			m, err := NewDocument()
+			if err != nil {
+				t.Fatal(err)
+			}
+			m.ColumnDelimiter = ","
+			if strings.HasPrefix(tt.str, "|") {
+				m.ColumnDelimiter = "|"
+			}
+			var got []string
+			for _, column := range m.alignColumns(parseString(tt.str, 1), tt.tabWidth) {
+				str, _ := ContentsToStr(column)
+				got = append(got, str)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("alignColumns() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocument_alignColumnsStyle(t *testing.T) {
+	t.Parallel()
+	m, err := NewDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.ColumnDelimiter = ","
+	columns := m.alignColumns(parseString("\x1b[31mred\x1b[m,b\tc", 1), 4)
+	if len(columns) != 2 {
+		t.Fatalf("alignColumns() = %d columns, want 2", len(columns))
+	}
+	fg, _, _ := columns[0][0].style.Decompose()
+	if fg != tcell.ColorMaroon {
+		t.Errorf("alignColumns() foreground = %v, want %v", fg, tcell.ColorMaroon)
+	}
+	// The tab is expanded to the tab stop in the column.
+	if got := len(columns[1]); got != 5 {
+		t.Errorf("alignColumns() width = %d, want 5", got)
+	}
+}
+
+func TestDocument_alignContents(t *testing.T) {
+	t.Parallel()
+	m, err := NewDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := strings.NewReader("id,name,age\n1,\"Smith, John\",30\n22,Bob,4\nno delimiter\n")
+	if err := m.ControlReader(r, nil); err != nil {
+		t.Fatal(err)
+	}
+	for !m.BufEOF() {
+	}
+	m.ColumnDelimiter = ","
+	m.ColumnCSV = true
+	m.ColumnAlign = true
+	m.setAlignWidths()
+	if want := []int{3, 14, 3}; !reflect.DeepEqual(m.alignWidths, want) {
+		t.Fatalf("setAlignWidths() = %v, want %v", m.alignWidths, want)
+	}
+
+	tests := []struct {
+		lN       int
+		wantView string
+		wantStr  string
+	}{
+		{lN: 0, wantView: `id, name,          age`, wantStr: `id,name,age`},
+		{lN: 1, wantView: `1,  "Smith, John", 30`, wantStr: `1,"Smith, John",30`},
+		{lN: 2, wantView: `22, Bob,           4`, wantStr: `22,Bob,4`},
+		{lN: 3, wantView: `no delimiter`, wantStr: `no delimiter`},
+	}
+	for _, tt := range tests {
+		lc, err := m.contents(tt.lN, 8)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var view strings.Builder
+		for _, c := range lc {
+			if c.mainc == 0 {
+				view.WriteByte(' ')
+				continue
+			}
+			view.WriteRune(c.mainc)
+		}
+		if got := view.String(); got != tt.wantView {
+			t.Errorf("contents(%d) view = %q, want %q", tt.lN, got, tt.wantView)
+		}
+		// The padding is not included in the string of the line.
+		if got, _ := ContentsToStr(lc); got != tt.wantStr {
+			t.Errorf("contents(%d) str = %q, want %q", tt.lN, got, tt.wantStr)
+		}
+	}
+}
+
+func TestDocument_setAlignWidthsNoDelimiter(t *testing.T) {
+	t.Parallel()
+	m, err := NewDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := strings.NewReader("a b c\nd e f\n")
+	if err := m.ControlReader(r, nil); err != nil {
+		t.Fatal(err)
+	}
+	for !m.BufEOF() {
+	}
+	m.ColumnDelimiter = ","
+	m.ColumnAlign = true
+	if !m.needAlignWidths() {
+		t.Fatal("needAlignWidths() = false, want true")
+	}
+	m.setAlignWidths()
+	if len(m.alignWidths) != 0 {
+		t.Errorf("setAlignWidths() = %v, want empty", m.alignWidths)
+	}
+	// No line has the delimiter, but the widths are not computed again.
+	if m.needAlignWidths() {
+		t.Error("needAlignWidths() = true, want false")
+	}
+	m.clearAlignWidths()
+	if !m.needAlignWidths() {
+		t.Error("needAlignWidths() after clearAlignWidths() = false, want true")
+	}
+}
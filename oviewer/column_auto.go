@@ -0,0 +1,218 @@
+package oviewer
+
+import (
+	"strings"
+	"unicode"
+)
+
+// autoDelimiters is the candidates of the delimiter detected by ColumnAuto, in order of priority.
+var autoDelimiters = []string{"\t", ",", "|", ";", "│", "┃", "║"}
+
+// autoSampleLines is the number of lines to sample for ColumnAuto.
+const autoSampleLines = 1000
+
+// autoHeaderLines is the number of lines under the header to guess the header.
+const autoHeaderLines = 100
+
+// autoConsistency is the minimum ratio of the lines with the same number of delimiters.
+const autoConsistency = 0.6
+
+// autoRuleChars is the characters of the ruled line between the header and the body.
+const autoRuleChars = "-=+|: ─━═┼╋╬├┤┌┐└┘┬┴┠┨╞╡╪"
+
+// isRuleLine returns true if the line is a ruled line such as "----+----".
+func isRuleLine(s string) bool {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !strings.ContainsRune(autoRuleChars, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// delimiterCount returns the number of delimiters in the line.
+// The commas in the quoted fields are not counted.
+func delimiterCount(s string, delimiter string) int {
+	if delimiter == "," && strings.IndexByte(s, csvQuote) >= 0 {
+		return len(unquotedIndex(s, allIndex(s, delimiter, nil)))
+	}
+	return strings.Count(s, delimiter)
+}
+
+// detectDelimiter returns the most consistent delimiter of the lines.
+// It returns an empty string if no delimiter is consistent.
+func detectDelimiter(lines []string) string {
+	best, bestScore := "", 0.0
+	for _, delimiter := range autoDelimiters {
+		counts := make(map[int]int)
+		total := 0
+		for _, line := range lines {
+			if strings.TrimSpace(line) == "" || isRuleLine(line) {
+				continue
+			}
+			counts[delimiterCount(line, delimiter)]++
+			total++
+		}
+		mode, modeNum := 0, 0
+		for c, n := range counts {
+			if n > modeNum || (n == modeNum && c > mode) {
+				mode, modeNum = c, n
+			}
+		}
+		if mode == 0 || total == 0 {
+			continue
+		}
+		score := float64(modeNum) / float64(total)
+		if score >= autoConsistency && score > bestScore {
+			best, bestScore = delimiter, score
+		}
+	}
+	return best
+}
+
+// isNumber returns true if the field is a number.
+func isNumber(s string) bool {
+	_, ok := sortKey(s, sortNumeric)
+	return ok
+}
+
+// guessHeader returns true if the first line is probably the header.
+// split returns the fields of the line.
+func guessHeader(lines []string, split func(string) []string) bool {
+	if len(lines) < 2 {
+		return false
+	}
+	if isRuleLine(lines[1]) {
+		return true
+	}
+
+	header := split(lines[0])
+	hasLetter := false
+	upper := true
+	for _, f := range header {
+		f = strings.TrimSpace(f)
+		if isNumber(f) {
+			return false
+		}
+		for _, r := range f {
+			if unicode.IsLetter(r) {
+				hasLetter = true
+				if !unicode.IsUpper(r) {
+					upper = false
+				}
+			}
+		}
+	}
+	if !hasLetter {
+		return false
+	}
+
+	// A column with numbers under a name.
+	body := lines[1:min(len(lines), autoHeaderLines+1)]
+	for c := range header {
+		numbers, total := 0, 0
+		for _, line := range body {
+			fields := split(line)
+			if c >= len(fields) || strings.TrimSpace(fields[c]) == "" {
+				continue
+			}
+			total++
+			if isNumber(strings.TrimSpace(fields[c])) {
+				numbers++
+			}
+		}
+		if total > 0 && numbers*2 >= total {
+			return true
+		}
+	}
+	return upper
+}
+
+// columnAutoPending returns true if ColumnAuto has not detected the column yet.
+func (m *Document) columnAutoPending() bool {
+	return m.ColumnAuto && !m.columnDetected.Load()
+}
+
+// columnAutoDetect detects the column of the current document by ColumnAuto.
+// It is called when the lines are loaded and when the document is displayed,
+// and detects only once.
+func (root *Root) columnAutoDetect() {
+	m := root.Doc
+	if !m.columnAutoPending() || !m.detectColumn() {
+		return
+	}
+	m.columnDetected.Store(true)
+	root.setMessageLogf("Detected column delimiter %q header %d", m.ColumnDelimiter, m.Header)
+}
+
+// sampleLines returns the lines from start to end without escape sequences.
+// The lines are copied under the lock of the store and do not load the evicted chunks.
+func (m *Document) sampleLines(start int, end int) []string {
+	lines := make([]string, 0, max(end-start, 0))
+	for n := start; n < end; n++ {
+		line, err := m.store.GetChunkLine(chunkLineNum(n))
+		if err != nil {
+			break
+		}
+		lines = append(lines, stripEscapeSequenceString(string(line)))
+	}
+	return lines
+}
+
+// detectColumn detects the column delimiter and the header from the beginning of the document.
+// If no delimiter is found, it is ColumnWidth mode.
+// It returns false if there are not enough lines to detect yet.
+func (m *Document) detectColumn() bool {
+	if !m.BufEOF() && m.BufEndNum() < m.SkipLines+autoSampleLines {
+		return false
+	}
+	if m.BufEndNum() <= m.SkipLines {
+		return true
+	}
+
+	lines := m.sampleLines(m.SkipLines, min(m.SkipLines+autoSampleLines, m.BufEndNum()))
+
+	m.ColumnMode = true
+	delimiter := detectDelimiter(lines)
+	if delimiter == "" {
+		m.ColumnWidth = true
+		m.ColumnCSV = false
+		m.resetColumnWidths()
+		if m.Header == 0 && guessHeader(lines, strings.Fields) {
+			m.Header = 1
+		}
+		m.ClearCache()
+		return true
+	}
+
+	m.ColumnWidth = false
+	m.ColumnCSV = false
+	if delimiter == "," {
+		for _, line := range lines {
+			if strings.IndexByte(line, csvQuote) >= 0 {
+				m.ColumnCSV = true
+				break
+			}
+		}
+	}
+	m.setDelimiter(delimiter)
+	split := func(s string) []string {
+		indexes := m.delimiterIndex(s)
+		fields := make([]string, 0, len(indexes)+1)
+		p := 0
+		for _, idx := range indexes {
+			fields = append(fields, s[p:idx[0]])
+			p = idx[1]
+		}
+		return append(fields, s[p:])
+	}
+	if m.Header == 0 && guessHeader(lines, split) {
+		m.Header = 1
+	}
+	m.ClearCache()
+	return true
+}
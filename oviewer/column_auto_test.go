@@ -0,0 +1,203 @@
+package oviewer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_detectDelimiter(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		lines []string
+		want  string
+	}{
+		{
+			name:  "testCSV",
+			lines: []string{"id,name,age", `1,"Smith, John",30`, "2,Bob,4"},
+			want:  ",",
+		},
+		{
+			name:  "testTSV",
+			lines: []string{"id\tname, title", "1\tBob, Dr."},
+			want:  "\t",
+		},
+		{
+			name:  "testPsql",
+			lines: []string{" id | name ", "----+------", "  1 | Bob", "  2 | Alice", "(2 rows)", ""},
+			want:  "|",
+		},
+		{
+			name:  "testBox",
+			lines: []string{"│ id │ name │", "├────┼──────┤", "│ 1  │ Bob  │"},
+			want:  "│",
+		},
+		{
+			name:  "testSemicolon",
+			lines: []string{"a;b;c", "1;2;3"},
+			want:  ";",
+		},
+		{
+			name:  "testWidth",
+			lines: []string{"NAME   READY  AGE", "web-1  1/1    3d"},
+			want:  "",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := detectDelimiter(tt.lines); got != tt.want {
+				t.Errorf("detectDelimiter() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_guessHeader(t *testing.T) {
+	t.Parallel()
+	split := func(s string) []string {
+		return strings.Split(s, ",")
+	}
+	tests := []struct {
+		name  string
+		lines []string
+		want  bool
+	}{
+		{
+			name:  "testNumberColumn",
+			lines: []string{"name,age", "Bob,4", "Alice,30"},
+			want:  true,
+		},
+		{
+			name:  "testNoHeader",
+			lines: []string{"Bob,4", "Alice,30"},
+			want:  false,
+		},
+		{
+			name:  "testTextOnly",
+			lines: []string{"Bob,London", "Alice,Paris"},
+			want:  false,
+		},
+		{
+			name:  "testUpper",
+			lines: []string{"NAME,CITY", "Bob,London"},
+			want:  true,
+		},
+		{
+			name:  "testRule",
+			lines: []string{"name,city", "----+----", "Bob,London"},
+			want:  true,
+		},
+		{
+			name:  "testOneLine",
+			lines: []string{"name,age"},
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := guessHeader(tt.lines, split); got != tt.want {
+				t.Errorf("guessHeader() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocument_detectColumn(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		str           string
+		wantDelimiter string
+		wantCSV       bool
+		wantWidth     bool
+		wantHeader    int
+	}{
+		{
+			name:          "testCSV",
+			str:           "id,name\n1,\"Smith, John\"\n2,Bob\n",
+			wantDelimiter: ",",
+			wantCSV:       true,
+			wantHeader:    1,
+		},
+		{
+			name:          "testTSV",
+			str:           "Bob\tLondon\nAlice\tParis\n",
+			wantDelimiter: "\t",
+			wantHeader:    0,
+		},
+		{
+			name:          "testWidth",
+			str:           "NAME   READY  AGE\nweb-1  1/1    3d\n",
+			wantDelimiter: ",",
+			wantWidth:     true,
+			wantHeader:    1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			m, err := NewDocument()
+			if err != nil {
+				t.Fatal(err)
+			}
+			m.setDelimiter(",")
+			if err := m.ControlReader(strings.NewReader(tt.str), nil); err != nil {
+				t.Fatal(err)
+			}
+			for !m.BufEOF() {
+			}
+			if !m.detectColumn() {
+				t.Fatal("detectColumn() = false")
+			}
+			if !m.ColumnMode {
+				t.Error("ColumnMode = false")
+			}
+			if m.ColumnDelimiter != tt.wantDelimiter || m.ColumnCSV != tt.wantCSV || m.ColumnWidth != tt.wantWidth {
+				t.Errorf("delimiter, CSV, width = %q, %v, %v, want %q, %v, %v",
+					m.ColumnDelimiter, m.ColumnCSV, m.ColumnWidth, tt.wantDelimiter, tt.wantCSV, tt.wantWidth)
+			}
+			if m.Header != tt.wantHeader {
+				t.Errorf("Header = %d, want %d", m.Header, tt.wantHeader)
+			}
+		})
+	}
+}
+
+func TestDocument_sampleLines(t *testing.T) {
+	t.Parallel()
+	m, err := NewDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.ControlReader(strings.NewReader("a,b\r\n\x1b[31mc\x1b[m,d\n"), nil); err != nil {
+		t.Fatal(err)
+	}
+	for !m.BufEOF() {
+	}
+	want := []string{"a,b", "c,d"}
+	if got := m.sampleLines(0, 10); !reflect.DeepEqual(got, want) {
+		t.Errorf("sampleLines() = %q, want %q", got, want)
+	}
+}
+
+func TestDocument_columnAutoPending(t *testing.T) {
+	t.Parallel()
+	m, err := NewDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.ColumnAuto = true
+	if !m.columnAutoPending() {
+		t.Error("columnAutoPending() = false before detection")
+	}
+	m.columnDetected.Store(true)
+	if m.columnAutoPending() {
+		t.Error("columnAutoPending() = true after detection")
+	}
+}
@@ -0,0 +1,67 @@
+package oviewer
+
+import (
+	"strings"
+)
+
+// csvQuote is the quote character of CSV (RFC 4180).
+const csvQuote = '"'
+
+// unquotedIndex returns the indexes of the delimiters that are not in the quoted fields.
+// A field is quoted if it starts with a quote after optional spaces,
+// and a doubled quote in the quoted field is an escaped quote.
+// The quote that is not closed in the line is a literal quote,
+// because the lines are split before the fields (newlines in the quoted fields are not supported).
+func unquotedIndex(s string, indexes [][]int) [][]int {
+	result := make([][]int, 0, len(indexes))
+	inQuote := false
+	fieldStart := 0
+	quoteStart, quoteK := 0, 0
+	k := 0
+	for i := 0; i < len(s) || inQuote; {
+		if i >= len(s) {
+			// Scan again from the unclosed quote as a literal quote.
+			i, k = quoteStart+1, quoteK
+			inQuote = false
+			continue
+		}
+		for k < len(indexes) && indexes[k][0] < i {
+			k++
+		}
+		if !inQuote && k < len(indexes) && indexes[k][0] == i {
+			result = append(result, indexes[k])
+			i = max(indexes[k][1], i+1)
+			fieldStart = i
+			k++
+			continue
+		}
+		if s[i] == csvQuote {
+			switch {
+			case !inQuote:
+				inQuote = strings.TrimLeft(s[fieldStart:i], " ") == ""
+				quoteStart, quoteK = i, k
+			case i+1 < len(s) && s[i+1] == csvQuote:
+				i++
+			default:
+				inQuote = false
+			}
+		}
+		i++
+	}
+	return result
+}
+
+// delimiterIndex returns the indexes of the column delimiters in the line.
+// In ColumnCSV, the delimiters in the quoted fields are excluded.
+func (m *Document) delimiterIndex(str string) [][]int {
+	indexes := allIndex(str, m.ColumnDelimiter, m.ColumnDelimiterReg)
+	if m.ColumnCSV {
+		return unquotedIndex(str, indexes)
+	}
+	return indexes
+}
+
+// splitColumns returns the start positions of the columns in the line.
+func (m *Document) splitColumns(str string) []int {
+	return splitByIndex(str, m.delimiterIndex(str))
+}
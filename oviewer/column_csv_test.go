@@ -0,0 +1,100 @@
+package oviewer
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func Test_unquotedIndex(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		s         string
+		delimiter string
+		reg       *regexp.Regexp
+		want      [][]int
+	}{
+		{
+			name:      "testNoQuote",
+			s:         `a,b,c`,
+			delimiter: ",",
+			want:      [][]int{{1, 2}, {3, 4}},
+		},
+		{
+			name:      "testQuotedComma",
+			s:         `1,"Smith, John",3`,
+			delimiter: ",",
+			want:      [][]int{{1, 2}, {15, 16}},
+		},
+		{
+			name:      "testEscapedQuote",
+			s:         `"a ""b, c"" d",e`,
+			delimiter: ",",
+			want:      [][]int{{14, 15}},
+		},
+		{
+			name:      "testQuoteInField",
+			s:         `a"b,c`,
+			delimiter: ",",
+			want:      [][]int{{3, 4}},
+		},
+		{
+			name:      "testSpaceBeforeQuote",
+			s:         `a, "b,c",d`,
+			delimiter: ",",
+			want:      [][]int{{1, 2}, {8, 9}},
+		},
+		{
+			name:      "testUnterminated",
+			s:         `a,"b,c`,
+			delimiter: ",",
+			want:      [][]int{{1, 2}, {4, 5}},
+		},
+		{
+			name:      "testUnterminatedEscapedQuote",
+			s:         `x,"a,""b,c`,
+			delimiter: ",",
+			want:      [][]int{{1, 2}, {4, 5}, {8, 9}},
+		},
+		{
+			name:      "testTab",
+			s:         "a\t\"b\tc\"\td",
+			delimiter: "\t",
+			want:      [][]int{{1, 2}, {7, 8}},
+		},
+		{
+			name: "testRegexp",
+			s:    `a  "b  c"  d`,
+			reg:  regexp.MustCompile(`\s+`),
+			want: [][]int{{1, 3}, {9, 11}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			indexes := allIndex(tt.s, tt.delimiter, tt.reg)
+			if got := unquotedIndex(tt.s, indexes); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("unquotedIndex() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocument_splitColumns(t *testing.T) {
+	t.Parallel()
+	m, err := NewDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.ColumnDelimiter = ","
+	str := `1,"Smith, John",3`
+	if got, want := m.splitColumns(str), []int{0, 3, 10, 17}; !reflect.DeepEqual(got, want) {
+		t.Errorf("splitColumns() = %v, want %v", got, want)
+	}
+	m.ColumnCSV = true
+	if got, want := m.splitColumns(str), []int{0, 3, 17}; !reflect.DeepEqual(got, want) {
+		t.Errorf("splitColumns() with ColumnCSV = %v, want %v", got, want)
+	}
+}
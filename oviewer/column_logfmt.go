@@ -0,0 +1,185 @@
+package oviewer
+
+import (
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// logfmtPair represents a key=value pair of logfmt.
+// start, value and end are the byte positions in the line.
+type logfmtPair struct {
+	key   string
+	start int
+	value int
+	end   int
+}
+
+// parseLogfmt returns the key=value pairs of the logfmt line.
+// It returns nil if the line is not logfmt.
+func parseLogfmt(s string) []logfmtPair {
+	var pairs []logfmtPair
+	for i := 0; i < len(s); {
+		if s[i] == ' ' || s[i] == '\t' {
+			i++
+			continue
+		}
+		start := i
+		for i < len(s) && strings.IndexByte("= \t\"", s[i]) < 0 {
+			i++
+		}
+		if i == start || i >= len(s) || s[i] != '=' {
+			return nil
+		}
+		key := s[start:i]
+		i++
+		value := i
+		if i < len(s) && s[i] == '"' {
+			i = jsonStringEnd(s, i)
+		}
+		for i < len(s) && s[i] != ' ' && s[i] != '\t' {
+			i++
+		}
+		pairs = append(pairs, logfmtPair{key: key, start: start, value: value, end: i})
+	}
+	return pairs
+}
+
+// logfmtColumn returns the column number of the key.
+// It returns -1 if the key is not a column.
+func logfmtColumn(keys []string, key string) int {
+	for c, k := range keys {
+		if k == key {
+			return c
+		}
+	}
+	return -1
+}
+
+// logfmtColumns returns the keys in the order of appearance
+// and the maximum width of the key=value of each key.
+func logfmtColumns(lines []string) ([]string, []int) {
+	var keys []string
+	var widths []int
+	for _, line := range lines {
+		s := stripEscapeSequenceString(line)
+		for _, p := range parseLogfmt(s) {
+			c := logfmtColumn(keys, p.key)
+			if c < 0 {
+				keys = append(keys, p.key)
+				widths = append(widths, 0)
+				c = len(keys) - 1
+			}
+			widths[c] = max(widths[c], runewidth.StringWidth(s[p.start:p.end]))
+		}
+	}
+	return keys, widths
+}
+
+// setLogfmtColumns sets the columns of logfmt from the beginning of the document.
+func (m *Document) setLogfmtColumns() {
+	if m.BufEndNum() == 0 {
+		return
+	}
+
+	tl := min(1000, m.BufEndNum())
+	buf := make([]string, 0, tl)
+	for n := min(m.firstLine(), tl); n < tl; n++ {
+		line, err := m.store.GetChunkLine(chunkLineNum(n))
+		if err != nil {
+			break
+		}
+		buf = append(buf, string(line))
+	}
+	m.logfmtKeys, m.logfmtWidths = logfmtColumns(buf)
+}
+
+// logfmtPositions returns the start positions of the columns after the first column.
+func (m *Document) logfmtPositions() []int {
+	if len(m.logfmtWidths) == 0 {
+		return nil
+	}
+	positions := make([]int, 0, len(m.logfmtWidths)-1)
+	pos := 0
+	for _, w := range m.logfmtWidths[:len(m.logfmtWidths)-1] {
+		pos += w + 1
+		positions = append(positions, pos)
+	}
+	return positions
+}
+
+// logfmtContents returns the contents of the logfmt line aligned to the columns.
+// The keys that are not columns are added after the columns.
+// It returns false if the line is not logfmt.
+func logfmtContents(str string, keys []string, widths []int, tabWidth int) (contents, bool) {
+	s := stripEscapeSequenceString(str)
+	pairs := parseLogfmt(s)
+	if len(pairs) == 0 {
+		return nil, false
+	}
+
+	used := make([]bool, len(pairs))
+	var b strings.Builder
+	pad := 0
+	for c, key := range keys {
+		if c > 0 {
+			pad++
+		}
+		i := -1
+		for n, p := range pairs {
+			if !used[n] && p.key == key {
+				i = n
+				break
+			}
+		}
+		if i < 0 {
+			pad += widths[c]
+			continue
+		}
+		used[i] = true
+		pair := s[pairs[i].start:pairs[i].end]
+		b.WriteString(strings.Repeat(" ", pad))
+		b.WriteString(pair)
+		pad = max(0, widths[c]-runewidth.StringWidth(pair))
+	}
+	for n, p := range pairs {
+		if used[n] {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(s[p.start:p.end])
+	}
+	return parseString(b.String(), tabWidth), true
+}
+
+// logfmtColumnRange returns the byte range of the value of the key.
+// It returns -1 if the line does not have the key.
+func logfmtColumnRange(s string, key string) (int, int) {
+	for _, p := range parseLogfmt(s) {
+		if p.key == key {
+			return p.value, p.end
+		}
+	}
+	return -1, -1
+}
+
+// columnLogfmtHighlight applies the style of the column highlight to the key=value pairs.
+func (root *Root) columnLogfmtHighlight(line LineC) {
+	m := root.Doc
+	numC := len(root.StyleColumnRainbow)
+	for _, p := range parseLogfmt(line.str) {
+		c := logfmtColumn(m.logfmtKeys, p.key)
+		if c < 0 {
+			continue
+		}
+		start, end := line.pos.x(p.start), line.pos.x(p.end)
+		if m.ColumnRainbow {
+			RangeStyle(line.lc, start, end, root.StyleColumnRainbow[c%numC])
+		}
+		if c == m.columnCursor {
+			RangeStyle(line.lc, start, end, root.StyleColumnHighlight)
+		}
+	}
+}
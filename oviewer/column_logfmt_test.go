@@ -0,0 +1,162 @@
+package oviewer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_parseLogfmt(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		str  string
+		want []logfmtPair
+	}{
+		{
+			name: "testPairs",
+			str:  `level=info msg="hello world" n=1`,
+			want: []logfmtPair{
+				{key: "level", start: 0, value: 6, end: 10},
+				{key: "msg", start: 11, value: 15, end: 28},
+				{key: "n", start: 29, value: 31, end: 32},
+			},
+		},
+		{
+			name: "testQuoteEscape",
+			str:  `msg="a \" b" empty=`,
+			want: []logfmtPair{
+				{key: "msg", start: 0, value: 4, end: 12},
+				{key: "empty", start: 13, value: 19, end: 19},
+			},
+		},
+		{
+			name: "testNotLogfmt",
+			str:  `hello world`,
+			want: nil,
+		},
+		{
+			name: "testValueWithoutKey",
+			str:  `a=1 =2`,
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := parseLogfmt(tt.str); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseLogfmt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_logfmtColumns(t *testing.T) {
+	t.Parallel()
+	lines := []string{
+		`level=info msg=start`,
+		`not logfmt`,
+		`level=error msg=failed err="no file"`,
+	}
+	keys, widths := logfmtColumns(lines)
+	if want := []string{"level", "msg", "err"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("logfmtColumns() keys = %v, want %v", keys, want)
+	}
+	if want := []int{11, 10, 13}; !reflect.DeepEqual(widths, want) {
+		t.Errorf("logfmtColumns() widths = %v, want %v", widths, want)
+	}
+}
+
+func Test_logfmtContents(t *testing.T) {
+	t.Parallel()
+	keys := []string{"level", "msg", "err"}
+	widths := []int{11, 10, 13}
+	tests := []struct {
+		name   string
+		str    string
+		want   string
+		wantOK bool
+	}{
+		{
+			name:   "testAligned",
+			str:    `level=info msg=start`,
+			want:   `level=info  msg=start`,
+			wantOK: true,
+		},
+		{
+			name:   "testOrder",
+			str:    `err="no file" level=error`,
+			want:   `level=error            err="no file"`,
+			wantOK: true,
+		},
+		{
+			name:   "testOtherKey",
+			str:    `msg=end user=ov`,
+			want:   `            msg=end user=ov`,
+			wantOK: true,
+		},
+		{
+			name:   "testEscapeSequence",
+			str:    "\x1b[31mlevel=error\x1b[m msg=x",
+			want:   `level=error msg=x`,
+			wantOK: true,
+		},
+		{
+			name:   "testNotLogfmt",
+			str:    `not logfmt`,
+			wantOK: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			lc, ok := logfmtContents(tt.str, keys, widths, 8)
+			if ok != tt.wantOK {
+				t.Fatalf("logfmtContents() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if got, _ := ContentsToStr(lc); got != tt.want {
+				t.Errorf("logfmtContents() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocument_logfmtColumn(t *testing.T) {
+	t.Parallel()
+	m, err := NewDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := strings.NewReader("level=info msg=start\nlevel=error msg=failed err=x\n")
+	if err := m.ControlReader(r, nil); err != nil {
+		t.Fatal(err)
+	}
+	for !m.BufEOF() {
+	}
+	m.ColumnLogfmt = true
+	m.ColumnMode = true
+	m.setLogfmtColumns()
+	if want := []int{12, 23}; !reflect.DeepEqual(m.logfmtPositions(), want) {
+		t.Errorf("logfmtPositions() = %v, want %v", m.logfmtPositions(), want)
+	}
+	if got := m.rightmostColumn(); got != 2 {
+		t.Errorf("rightmostColumn() = %d, want 2", got)
+	}
+	if got, err := m.optimalX(2); err != nil || got != 23 {
+		t.Errorf("optimalX(2) = %d, %v, want 23", got, err)
+	}
+
+	m.columnCursor = 1
+	searcher := m.columnSearcher(NewSearcher("start", nil, false, false))
+	if !searcher.MatchString("level=info msg=start") {
+		t.Error("column search did not match the value of msg")
+	}
+	if searcher.MatchString("level=start msg=end") {
+		t.Error("column search matched the value of level")
+	}
+}
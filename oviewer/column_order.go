@@ -0,0 +1,356 @@
+package oviewer
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// orderBlank is the blank that pads the ordered columns of ColumnWidth.
+// It is a space, because the columns of ColumnWidth are separated by spaces.
+var orderBlank = content{
+	mainc: ' ',
+	combc: nil,
+	width: 1,
+	style: tcell.StyleDefault,
+}
+
+// parseColumnOrder returns the column numbers (1-based) separated by commas or spaces.
+// The empty string returns nil, which displays all columns.
+func parseColumnOrder(str string) ([]int, error) {
+	fields := strings.FieldsFunc(str, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	order := make([]int, 0, len(fields))
+	for _, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			return nil, ErrInvalidNumber
+		}
+		if n < 1 {
+			return nil, ErrOutOfRange
+		}
+		order = append(order, n)
+	}
+	return order, nil
+}
+
+// columnOrderString returns the column order as the input string.
+func columnOrderString(order []int) string {
+	s := make([]string, len(order))
+	for n, c := range order {
+		s[n] = strconv.Itoa(c)
+	}
+	return strings.Join(s, ",")
+}
+
+// columnOrdered returns true if the columns are reordered or hidden.
+// ColumnLogfmt has its own order of the keys, so it is not ordered.
+func (m *Document) columnOrdered() bool {
+	return m.ColumnMode && !m.ColumnLogfmt && len(m.ColumnOrder) > 0
+}
+
+// rawColumn returns the column number of the original line from the displayed column.
+// It returns -1 if the displayed column does not exist.
+func (m *Document) rawColumn(c int) int {
+	if !m.columnOrdered() {
+		return c
+	}
+	if c < 0 || c >= len(m.ColumnOrder) {
+		return -1
+	}
+	return m.ColumnOrder[c] - 1
+}
+
+// setColumnOrder sets the order of the displayed columns.
+func (m *Document) setColumnOrder(order []int) {
+	m.ColumnOrder = order
+	// The width of the last column is required for ColumnWidth.
+	m.resetColumnWidths()
+	m.resetAlignWidths()
+	m.ClearCache()
+}
+
+// clearOrderCache clears the cache of the lines with the ordered columns,
+// because the order depends on the column mode and the delimiter.
+func (m *Document) clearOrderCache() {
+	if len(m.ColumnOrder) == 0 {
+		return
+	}
+	m.ClearCache()
+}
+
+// orderContents returns the contents with the columns separated by the delimiter in ColumnOrder.
+// The columns that are not in ColumnOrder are hidden.
+// The contents are parsed with the tab width 1, so that the tabs are expanded after the columns are moved.
+func (m *Document) orderContents(lc contents) contents {
+	s, pos := ContentsToStr(lc)
+	indexes := m.delimiterIndex(s)
+	if len(indexes) == 0 {
+		return lc
+	}
+
+	// The fences at both ends are kept in place.
+	start, end := 0, len(s)
+	if indexes[0][0] == 0 {
+		start = indexes[0][1]
+		indexes = indexes[1:]
+	}
+	if n := len(indexes); n > 0 && indexes[n-1][1] == len(s) {
+		end = indexes[n-1][0]
+		indexes = indexes[:n-1]
+	}
+	if start > end {
+		return lc
+	}
+
+	columns := make([]contents, 0, len(indexes)+1)
+	delimiters := make([]contents, 0, len(indexes))
+	p := pos.x(start)
+	for _, idx := range indexes {
+		columns = append(columns, lc[p:pos.x(idx[0])])
+		delimiters = append(delimiters, lc[pos.x(idx[0]):pos.x(idx[1])])
+		p = pos.x(idx[1])
+	}
+	columns = append(columns, lc[p:pos.x(end)])
+
+	olc := make(contents, 0, len(lc))
+	olc = append(olc, lc[:pos.x(start)]...)
+	for n, c := range m.ColumnOrder {
+		c--
+		if c < len(columns) {
+			olc = append(olc, columns[c]...)
+		}
+		if n == len(m.ColumnOrder)-1 {
+			break
+		}
+		switch {
+		case c < len(delimiters):
+			olc = append(olc, delimiters[c]...)
+		case len(delimiters) > 0:
+			olc = append(olc, delimiters[0]...)
+		default:
+			olc = append(olc, parseString(m.ColumnDelimiter, 1)...)
+		}
+	}
+	return append(olc, lc[pos.x(end):]...)
+}
+
+// widthColumnRanges returns the ranges of the columns of the contents in ColumnWidth mode.
+// The ranges do not include the blank that separates the columns.
+func widthColumnRanges(lc contents, widths []int) [][]int {
+	if len(widths) == 0 {
+		return nil
+	}
+	ranges := make([][]int, 0, len(widths)+1)
+	iStart, iEnd := 0, 0
+	for c := 0; c < len(widths)+1; c++ {
+		switch {
+		case c == 0:
+			iStart = 0
+			iEnd = findBounds(lc, widths[0]-1, widths, c)
+		case c < len(widths):
+			iStart = iEnd + 1
+			iEnd = findBounds(lc, widths[c], widths, c)
+		case c == len(widths):
+			iStart = iEnd + 1
+			iEnd = len(lc)
+		}
+		iEnd = min(iEnd, len(lc))
+		ranges = append(ranges, []int{min(iStart, iEnd), iEnd})
+	}
+	return ranges
+}
+
+// columnSlot returns the width of the column of the original line including a blank.
+func (m *Document) columnSlot(c int) int {
+	widths := m.columnWidths
+	if len(widths) == 0 || c > len(widths) {
+		return 1
+	}
+	if c == len(widths) {
+		return m.columnLastWidth + 1
+	}
+	// The positions are the blanks between the columns.
+	if c == 0 {
+		return widths[0] + 1
+	}
+	return widths[c] - widths[c-1]
+}
+
+// widthPositions returns the start positions of the displayed columns
+// after the first column in ColumnWidth mode.
+func (m *Document) widthPositions() []int {
+	if !m.columnOrdered() {
+		return m.columnWidths
+	}
+	positions := make([]int, 0, len(m.ColumnOrder)-1)
+	pos := 0
+	for _, c := range m.ColumnOrder[:len(m.ColumnOrder)-1] {
+		pos += m.columnSlot(c - 1)
+		positions = append(positions, pos)
+	}
+	return positions
+}
+
+// orderWidthContents returns the contents with the columns in ColumnOrder in ColumnWidth mode.
+// Each column is padded to its width so that the columns are aligned.
+func (m *Document) orderWidthContents(lc contents) contents {
+	ranges := widthColumnRanges(lc, m.columnWidths)
+	if len(ranges) == 0 {
+		return lc
+	}
+	olc := make(contents, 0, len(lc))
+	for n, c := range m.ColumnOrder {
+		c--
+		w := 0
+		if c < len(ranges) {
+			olc = append(olc, lc[ranges[c][0]:ranges[c][1]]...)
+			w = ranges[c][1] - ranges[c][0]
+		}
+		if n == len(m.ColumnOrder)-1 {
+			break
+		}
+		// At least one blank between the columns.
+		slot := max(m.columnSlot(c), w+1)
+		for ; w < slot; w++ {
+			olc = append(olc, orderBlank)
+		}
+	}
+	return olc
+}
+
+// setColumnLastWidth sets the width of the last column in ColumnWidth mode.
+func (m *Document) setColumnLastWidth(lines []string) {
+	m.columnLastWidth = 0
+	if len(m.columnWidths) == 0 {
+		return
+	}
+	last := m.columnWidths[len(m.columnWidths)-1]
+	for _, line := range lines {
+		m.columnLastWidth = max(m.columnLastWidth, len(parseString(line, m.TabWidth))-last-1)
+	}
+}
+
+// pinnedWidth returns the width of the pinned columns of the contents.
+// It returns 0 if the columns are not pinned or the line does not have the columns.
+func (m *Document) pinnedWidth(lc contents) int {
+	if !m.ColumnMode || m.WrapMode || m.ColumnPin <= 0 {
+		return 0
+	}
+
+	var positions []int
+	switch {
+	case m.ColumnLogfmt:
+		positions = m.logfmtPositions()
+	case m.ColumnWidth:
+		positions = m.widthPositions()
+	default:
+		str, pos := ContentsToStr(lc)
+		indexes := m.delimiterIndex(str)
+		if len(indexes) > 0 && indexes[0][0] == 0 {
+			indexes = indexes[1:]
+		}
+		if m.ColumnPin > len(indexes) {
+			return 0
+		}
+		return pos.x(indexes[m.ColumnPin-1][1])
+	}
+	if m.ColumnPin > len(positions) {
+		return 0
+	}
+	return min(positions[m.ColumnPin-1], len(lc))
+}
+
+// pinnedScreenWidth returns the width of the pinned columns on the screen.
+func (m *Document) pinnedScreenWidth() int {
+	if m.ColumnPin <= 0 {
+		return 0
+	}
+	for i := 0; i < TargetLineDelimiter; i++ {
+		line, valid := m.getLineC(m.topLN+m.firstLine()+i, m.TabWidth)
+		if !valid {
+			continue
+		}
+		if w := m.pinnedWidth(line.lc); w > 0 {
+			return w
+		}
+	}
+	return 0
+}
+
+// hideColumn hides the column of the cursor.
+func (root *Root) hideColumn() {
+	m := root.Doc
+	if !m.ColumnMode || m.ColumnLogfmt {
+		root.setMessage("Hide column: not column mode")
+		return
+	}
+
+	order := m.ColumnOrder
+	if len(order) == 0 {
+		order = make([]int, m.rightmostColumn()+1)
+		for n := range order {
+			order[n] = n + 1
+		}
+	}
+	if m.columnCursor >= len(order) {
+		root.setMessagef("Hide column: %s", ErrNoColumn.Error())
+		return
+	}
+	if len(order) == 1 {
+		root.setMessage("Hide column: cannot hide all columns")
+		return
+	}
+
+	hidden := order[m.columnCursor]
+	newOrder := make([]int, 0, len(order)-1)
+	newOrder = append(newOrder, order[:m.columnCursor]...)
+	newOrder = append(newOrder, order[m.columnCursor+1:]...)
+	m.setColumnOrder(newOrder)
+	m.columnCursor = min(m.columnCursor, len(newOrder)-1)
+	root.setMessagef("Hide column %d", hidden)
+}
+
+// setColumnOrder sets the order of the displayed columns from the input.
+func (root *Root) setColumnOrder(input string) {
+	m := root.Doc
+	order, err := parseColumnOrder(input)
+	if err != nil {
+		root.setMessagef("Set column order %s: %s", input, err.Error())
+		return
+	}
+	m.setColumnOrder(order)
+	if len(order) > 0 {
+		m.ColumnMode = true
+		m.columnCursor = min(m.columnCursor, len(order)-1)
+	}
+	m.x = 0
+	if len(order) == 0 {
+		root.setMessage("Show all columns")
+		return
+	}
+	root.setMessagef("Set column order %s", columnOrderString(order))
+}
+
+// setColumnPin sets the number of the pinned columns.
+func (root *Root) setColumnPin(input string) {
+	num, err := strconv.Atoi(input)
+	if err != nil {
+		root.setMessagef("Set pinned columns: %s", ErrInvalidNumber.Error())
+		return
+	}
+	if num < 0 {
+		root.setMessagef("Set pinned columns: %s", ErrOutOfRange.Error())
+		return
+	}
+	root.Doc.ColumnPin = num
+	if num > 0 {
+		root.Doc.ColumnMode = true
+	}
+	root.setMessagef("Set pinned columns %d", num)
+}
@@ -0,0 +1,237 @@
+package oviewer
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func Test_parseColumnOrder(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		str     string
+		want    []int
+		wantErr bool
+	}{
+		{name: "testEmpty", str: "", want: nil},
+		{name: "testComma", str: "3,1,2", want: []int{3, 1, 2}},
+		{name: "testSpace", str: " 2 1 ", want: []int{2, 1}},
+		{name: "testInvalid", str: "1,a", wantErr: true},
+		{name: "testZero", str: "0,1", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := parseColumnOrder(tt.str)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseColumnOrder() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseColumnOrder() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocument_orderContents(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		delimiter string
+		csv       bool
+		order     []int
+		str       string
+		want      string
+	}{
+		{
+			name:      "testReorder",
+			delimiter: ",",
+			order:     []int{3, 1, 2},
+			str:       "a,b,c",
+			want:      "c,a,b",
+		},
+		{
+			name:      "testHide",
+			delimiter: ",",
+			order:     []int{1, 3},
+			str:       "a,b,c,d",
+			want:      "a,c",
+		},
+		{
+			name:      "testFence",
+			delimiter: "|",
+			order:     []int{2, 1},
+			str:       "|a|b|",
+			want:      "|b|a|",
+		},
+		{
+			name:      "testShortLine",
+			delimiter: ",",
+			order:     []int{3, 1},
+			str:       "a,b",
+			want:      ",a",
+		},
+		{
+			name:      "testCSV",
+			delimiter: ",",
+			csv:       true,
+			order:     []int{2, 1},
+			str:       `1,"Smith, John"`,
+			want:      `"Smith, John",1`,
+		},
+		{
+			name:      "testNoDelimiter",
+			delimiter: ",",
+			order:     []int{2, 1},
+			str:       "no delimiter",
+			want:      "no delimiter",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			m, err := NewDocument()
+			if err != nil {
+				t.Fatal(err)
+			}
+			m.setDelimiter(tt.delimiter)
+			m.ColumnCSV = tt.csv
+			m.ColumnOrder = tt.order
+			if got, _ := ContentsToStr(m.orderContents(parseString(tt.str, 1))); got != tt.want {
+				t.Errorf("orderContents() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocument_orderContentsStyle(t *testing.T) {
+	t.Parallel()
+	m, err := NewDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.setDelimiter(",")
+	m.ColumnOrder = []int{2, 1}
+	lc := m.orderContents(parseString("\x1b[31mred\x1b[m,b", 1))
+	if got, _ := ContentsToStr(lc); got != "b,red" {
+		t.Fatalf("orderContents() = %q, want %q", got, "b,red")
+	}
+	// The style of the escape sequence moves with the column.
+	fg, _, _ := lc[2].style.Decompose()
+	if fg != tcell.ColorMaroon {
+		t.Errorf("orderContents() foreground = %v, want %v", fg, tcell.ColorMaroon)
+	}
+	if fg, _, _ := lc[0].style.Decompose(); fg == tcell.ColorMaroon {
+		t.Errorf("orderContents() foreground of the moved column = %v", fg)
+	}
+}
+
+func TestDocument_orderWidthContents(t *testing.T) {
+	t.Parallel()
+	m, err := NewDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := strings.NewReader("NAME   READY  AGE\nweb-1  1/1    3d\nweb-22 0/1    12h\n")
+	if err := m.ControlReader(r, nil); err != nil {
+		t.Fatal(err)
+	}
+	for !m.BufEOF() {
+	}
+	m.ColumnMode = true
+	m.ColumnWidth = true
+	m.Header = 1
+	m.setColumnOrder([]int{3, 1})
+	m.setColumnWidths()
+	if want := []int{6, 13}; !reflect.DeepEqual(m.columnWidths, want) {
+		t.Fatalf("setColumnWidths() = %v, want %v", m.columnWidths, want)
+	}
+	if want := []int{4}; !reflect.DeepEqual(m.widthPositions(), want) {
+		t.Fatalf("widthPositions() = %v, want %v", m.widthPositions(), want)
+	}
+
+	want := []string{"AGE NAME ", "3d  web-1", "12h web-22"}
+	for lN, w := range want {
+		lc, err := m.contents(lN, 8)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := ContentsToStr(lc); got != w {
+			t.Errorf("contents(%d) = %q, want %q", lN, got, w)
+		}
+	}
+}
+
+func TestRoot_hideColumn(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root, err := NewRoot(bytes.NewBufferString("a,b,c\n1,2,3\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for !root.Doc.BufEOF() {
+	}
+	m := root.Doc
+	m.ColumnMode = true
+	m.setDelimiter(",")
+	m.columnCursor = 1
+	root.hideColumn()
+	if want := []int{1, 3}; !reflect.DeepEqual(m.ColumnOrder, want) {
+		t.Fatalf("hideColumn() = %v, want %v", m.ColumnOrder, want)
+	}
+	if got := m.LineString(0); got != "a,b,c" {
+		t.Errorf("original line = %q, want %q", got, "a,b,c")
+	}
+	line, _ := m.getLineC(0, m.TabWidth)
+	if line.str != "a,c" {
+		t.Errorf("displayed line = %q, want %q", line.str, "a,c")
+	}
+	// The search in the column uses the original column.
+	cw, ok := m.cursorColumn()
+	if !ok || cw.column != 2 {
+		t.Errorf("cursorColumn() = %v, %v, want 2, true", cw.column, ok)
+	}
+}
+
+func TestRoot_drawNoWrapLinePin(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root, err := NewRoot(bytes.NewBufferString("id,name,description\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for !root.Doc.BufEOF() {
+	}
+	m := root.Doc
+	m.ColumnMode = true
+	m.WrapMode = false
+	m.setDelimiter(",")
+	m.ColumnPin = 1
+	root.scr.vWidth = 10
+	root.scr.startX = 0
+	line, _ := m.getLineC(0, m.TabWidth)
+	if got := m.pinnedWidth(line.lc); got != 3 {
+		t.Fatalf("pinnedWidth() = %d, want 3", got)
+	}
+
+	// The scrolled columns are drawn after the pinned columns.
+	root.drawNoWrapLine(0, 5, 0, line.lc)
+	var b strings.Builder
+	for x := 0; x < 10; x++ {
+		r, _, _, _ := root.Screen.GetContent(x, 0)
+		b.WriteRune(r)
+	}
+	if got, want := b.String(), "id,descrip"; got != want {
+		t.Errorf("drawNoWrapLine() = %q, want %q", got, want)
+	}
+}
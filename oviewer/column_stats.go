@@ -0,0 +1,212 @@
+package oviewer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// statsTopN is the number of the most frequent values in the column statistics.
+const statsTopN = 10
+
+// columnStats is the statistics of the values of a column.
+type columnStats struct {
+	freq    map[string]int
+	lines   int
+	count   int
+	numbers int
+	sum     float64
+	min     float64
+	max     float64
+	minStr  string
+	maxStr  string
+}
+
+// statsValue is a value and the number of its occurrences.
+type statsValue struct {
+	value string
+	count int
+}
+
+// newColumnStats returns columnStats.
+func newColumnStats() *columnStats {
+	return &columnStats{
+		freq: make(map[string]int),
+		min:  math.Inf(1),
+		max:  math.Inf(-1),
+	}
+}
+
+// add adds the value of a line to the statistics.
+// The empty value is counted as a line without the column.
+func (s *columnStats) add(value string) {
+	s.lines++
+	if value == "" {
+		return
+	}
+	if s.count == 0 || value < s.minStr {
+		s.minStr = value
+	}
+	if s.count == 0 || value > s.maxStr {
+		s.maxStr = value
+	}
+	s.count++
+	s.freq[value]++
+	if n, ok := sortKey(value, sortNumeric); ok {
+		s.numbers++
+		s.sum += n
+		s.min = math.Min(s.min, n)
+		s.max = math.Max(s.max, n)
+	}
+}
+
+// top returns the n most frequent values.
+// The values with the same number of occurrences are in lexical order.
+func (s *columnStats) top(n int) []statsValue {
+	values := make([]statsValue, 0, len(s.freq))
+	for v, c := range s.freq {
+		values = append(values, statsValue{value: v, count: c})
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].count != values[j].count {
+			return values[i].count > values[j].count
+		}
+		return values[i].value < values[j].value
+	})
+	return values[:min(n, len(values))]
+}
+
+// report returns the lines of the statistics.
+// min and max are numerical if all values are numbers, otherwise lexical.
+func (s *columnStats) report(title string) []string {
+	lines := []string{
+		title,
+		"",
+		fmt.Sprintf("%-10s%d", "lines", s.lines),
+		fmt.Sprintf("%-10s%d", "count", s.count),
+		fmt.Sprintf("%-10s%d", "empty", s.lines-s.count),
+		fmt.Sprintf("%-10s%d", "distinct", len(s.freq)),
+	}
+	if s.count == 0 {
+		return lines
+	}
+
+	minStr, maxStr := s.minStr, s.maxStr
+	if s.numbers == s.count {
+		minStr, maxStr = statsNumber(s.min), statsNumber(s.max)
+	}
+	lines = append(lines,
+		fmt.Sprintf("%-10s%s", "min", minStr),
+		fmt.Sprintf("%-10s%s", "max", maxStr),
+		fmt.Sprintf("%-10s%d", "numeric", s.numbers),
+	)
+	if s.numbers > 0 {
+		lines = append(lines,
+			fmt.Sprintf("%-10s%s", "sum", statsNumber(s.sum)),
+			fmt.Sprintf("%-10s%s", "mean", statsNumber(math.Round(s.sum/float64(s.numbers)*1e4)/1e4)),
+		)
+	}
+
+	lines = append(lines, "", fmt.Sprintf("Top %d", statsTopN), fmt.Sprintf("%10s %8s  %s", "count", "percent", "value"))
+	for _, v := range s.top(statsTopN) {
+		percent := float64(v.count) * 100 / float64(s.count)
+		lines = append(lines, fmt.Sprintf("%10d %7.1f%%  %s", v.count, percent, v.value))
+	}
+	return lines
+}
+
+// statsNumber returns the number as a string without the exponent.
+func statsNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// eventColumnStats represents the column statistics event.
+type eventColumnStats struct {
+	tcell.EventTime
+}
+
+// sendColumnStats fires the eventColumnStats event.
+func (root *Root) sendColumnStats() {
+	ev := &eventColumnStats{}
+	ev.SetEventNow()
+	root.postEvent(ev)
+}
+
+// columnStats computes the statistics of the column of the cursor into a new document.
+func (root *Root) columnStats(ctx context.Context) {
+	m := root.Doc
+	if !m.ColumnMode {
+		root.setMessage("Column statistics: not column mode")
+		return
+	}
+	cw, ok := m.cursorColumn()
+	if !ok {
+		root.setMessagef("Column statistics: %s", ErrNoColumn.Error())
+		return
+	}
+	target := fmt.Sprintf("column %d", cw.column+1)
+	if cw.key != "" {
+		target = cw.key
+	}
+
+	r, w := io.Pipe()
+	statsDoc, err := renderDoc(m, r)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	statsDoc.documentType = DocStats
+	statsDoc.FileName = fmt.Sprintf("stats:%s:%s", m.FileName, target)
+	statsDoc.Caption = fmt.Sprintf("%s:%s statistics", m.FileName, target)
+	root.addDocument(statsDoc.Document)
+	// The statistics are not the columns of the document.
+	statsDoc.Header = 0
+	statsDoc.SkipLines = 0
+	statsDoc.ColumnMode = false
+	statsDoc.ColumnLogfmt = false
+	statsDoc.ColumnAlign = false
+
+	statsDoc.writer = w
+	title := fmt.Sprintf("Statistics of %s in %s", target, m.FileName)
+	go m.statsWriter(ctx, cw.columnValue, title, statsDoc)
+	root.setMessagef("Column statistics of %s", target)
+}
+
+// statsWriter computes the statistics of the values of all lines
+// and writes the result to the statistics document.
+// The chunks that are not in memory are loaded in turn.
+func (m *Document) statsWriter(ctx context.Context, value func(string) string, title string, statsDoc *renderDocument) {
+	defer statsDoc.writer.Close()
+	stats := newColumnStats()
+	// The searcher that matches all lines loads the chunk.
+	loader := NewSearcher("", nil, true, false)
+	for chunkNum := 0; chunkNum <= m.store.lastChunkNum(); chunkNum++ {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+		if !m.isLoadedChunk(chunkNum) && !m.storageSearch(loader, chunkNum) {
+			continue
+		}
+		for n := 0; n < ChunkSize; n++ {
+			if chunkNum*ChunkSize+n < m.firstLine() {
+				continue
+			}
+			line, err := m.store.GetChunkLine(chunkNum, n)
+			if err != nil {
+				break
+			}
+			stats.add(value(string(line)))
+		}
+	}
+	for _, line := range stats.report(title) {
+		statsDoc.writeLine([]byte(line))
+	}
+}
@@ -0,0 +1,98 @@
+package oviewer
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func Test_columnStats(t *testing.T) {
+	t.Parallel()
+	stats := newColumnStats()
+	for _, v := range []string{"200", "404", "200", "", "500", "200"} {
+		stats.add(v)
+	}
+	if stats.lines != 6 || stats.count != 5 || len(stats.freq) != 3 {
+		t.Errorf("lines, count, distinct = %d, %d, %d, want 6, 5, 3", stats.lines, stats.count, len(stats.freq))
+	}
+	want := []statsValue{{value: "200", count: 3}, {value: "404", count: 1}}
+	if got := stats.top(2); !reflect.DeepEqual(got, want) {
+		t.Errorf("top() = %v, want %v", got, want)
+	}
+	wantReport := []string{
+		"title",
+		"",
+		"lines     6",
+		"count     5",
+		"empty     1",
+		"distinct  3",
+		"min       200",
+		"max       500",
+		"numeric   5",
+		"sum       1504",
+		"mean      300.8",
+		"",
+		"Top 10",
+		"     count  percent  value",
+		"         3    60.0%  200",
+		"         1    20.0%  404",
+		"         1    20.0%  500",
+	}
+	if got := stats.report("title"); !reflect.DeepEqual(got, wantReport) {
+		t.Errorf("report() = %q, want %q", got, wantReport)
+	}
+}
+
+func Test_columnStatsLexical(t *testing.T) {
+	t.Parallel()
+	stats := newColumnStats()
+	for _, v := range []string{"b", "10", "a"} {
+		stats.add(v)
+	}
+	if stats.numbers != 1 {
+		t.Errorf("numbers = %d, want 1", stats.numbers)
+	}
+	report := stats.report("title")
+	// min and max are lexical if there are values that are not numbers.
+	if report[6] != "min       10" || report[7] != "max       b" {
+		t.Errorf("report() min, max = %q, %q", report[6], report[7])
+	}
+}
+
+func TestDocument_statsWriter(t *testing.T) {
+	t.Parallel()
+	// More than one chunk.
+	m := openEOF(t, parallelSearchFile(t, 25000))
+	m.ColumnMode = true
+	m.setDelimiter(" ")
+	m.columnCursor = 1
+	cw, ok := m.cursorColumn()
+	if !ok {
+		t.Fatal("cursorColumn() = false")
+	}
+
+	r, w := io.Pipe()
+	statsDoc, err := renderDoc(m, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	statsDoc.writer = w
+	m.statsWriter(context.Background(), cw.columnValue, "title", statsDoc)
+	for !statsDoc.BufEOF() {
+	}
+
+	want := map[int]string{
+		2:  "lines     25000",
+		5:  "distinct  25000",
+		6:  "min       0",
+		7:  "max       24999",
+		9:  "sum       312487500",
+		10: "mean      12499.5",
+	}
+	for n, w := range want {
+		if got := statsDoc.LineString(n); got != w {
+			t.Errorf("LineString(%d) = %q, want %q", n, got, w)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package oviewer
+
+import (
+	"strings"
+
+	"github.com/noborus/guesswidth"
+)
+
+// widthSampleLines is the number of lines to guess the column widths of ColumnWidth.
+const widthSampleLines = 1000
+
+// setColumnWidths sets the column widths.
+// Guess the width of the columns using the lines (maximum 1000) around the top line and the headers.
+func (m *Document) setColumnWidths() {
+	if m.BufEndNum() == 0 {
+		return
+	}
+	m.guessColumnWidths(m.widthSampleStart(m.topLN + m.firstLine()))
+}
+
+// resetColumnWidths discards the column widths to guess them again.
+func (m *Document) resetColumnWidths() {
+	m.columnWidths = nil
+	m.columnWidthsGuessed = false
+}
+
+// updateColumnWidths guesses the column widths again,
+// if the top line has moved out of the lines that guessed the column widths,
+// or the lines were cut by the end of the loaded lines and more lines have been loaded.
+// It is called on every draw, so it returns early while the top line stays.
+func (m *Document) updateColumnWidths() {
+	if !m.columnWidthsGuessed {
+		m.setColumnWidths()
+		return
+	}
+	lN := max(m.topLN+m.firstLine(), m.firstLine())
+	if !(m.columnWidthsPartial && m.BufEndNum() > m.columnWidthsEnd) {
+		if lN == m.columnWidthsTop || (lN >= m.columnWidthsStart && lN < m.columnWidthsEnd) {
+			return
+		}
+	}
+	m.columnWidthsTop = lN
+	start := m.widthSampleStart(lN)
+	if start == m.columnWidthsStart && !(m.columnWidthsPartial && m.BufEndNum() > m.columnWidthsEnd) {
+		return
+	}
+	m.guessColumnWidths(start)
+}
+
+// widthSampleStart returns the first line of the lines to guess the column widths of the line.
+// It is the start of the section if SectionDelimiter is set,
+// otherwise the start of the block of widthSampleLines lines.
+func (m *Document) widthSampleStart(lN int) int {
+	lN = max(lN, m.firstLine())
+	if m.SectionDelimiter != "" {
+		if sectionLN, err := m.prevSection(lN + 1); err == nil && sectionLN >= m.firstLine() {
+			return sectionLN
+		}
+	}
+	return m.firstLine() + (lN-m.firstLine())/widthSampleLines*widthSampleLines
+}
+
+// guessColumnWidths guesses the column widths from the lines starting from start.
+// The lines end at the next section if SectionDelimiter is set.
+// The header of the document is used if the lines are not in a section,
+// but the lines are used by themselves if the values overflow the header
+// and the columns are fewer than before.
+func (m *Document) guessColumnWidths(start int) {
+	var searcher Searcher
+	if m.SectionDelimiter != "" {
+		searcher = NewSearcher(m.SectionDelimiter, m.SectionDelimiterReg, true, true)
+	}
+
+	lines := make([]string, 0, widthSampleLines)
+	m.columnWidthsPartial = false
+	end := start
+	for ; end < start+widthSampleLines; end++ {
+		line, err := m.Line(end)
+		if err != nil {
+			m.columnWidthsPartial = true
+			break
+		}
+		if searcher != nil && end > start && searcher.Match(line) {
+			break
+		}
+		lines = append(lines, string(line))
+	}
+
+	buf := lines
+	if m.Header > 0 && (searcher == nil || start == m.firstLine()) {
+		buf = append([]string{m.GetLine(m.firstLine() - 1)}, lines...)
+	}
+	widths := guesswidth.Positions(buf, widthHeader(buf), 2)
+	if start != m.firstLine() && len(widths) < len(m.columnWidths) {
+		if own := guesswidth.Positions(lines, widthHeader(lines), 2); len(own) >= len(m.columnWidths) {
+			widths = own
+			buf = lines
+		}
+	}
+
+	m.columnWidthsGuessed = true
+	m.columnWidthsTop = max(m.topLN+m.firstLine(), m.firstLine())
+	m.columnWidthsStart = start
+	m.columnWidthsEnd = end
+	m.columnWidths = widths
+	if m.columnOrdered() {
+		m.setColumnLastWidth(buf)
+		// The ordered columns of the cached lines depend on the column widths.
+		m.ClearCache()
+	}
+}
+
+// widthHeader returns the index of the line that is the reference of the blanks.
+// It is the first line that has a blank between the words,
+// because the delimiter line of the section may not be the header.
+func widthHeader(lines []string) int {
+	for n, line := range lines {
+		if strings.Contains(strings.TrimSpace(line), " ") {
+			return n
+		}
+	}
+	return 0
+}
@@ -0,0 +1,112 @@
+package oviewer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// widthDocument returns the document of the string that is read to the end.
+func widthDocument(t *testing.T, str string) *Document {
+	t.Helper()
+	m, err := NewDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.ControlReader(strings.NewReader(str), nil); err != nil {
+		t.Fatal(err)
+	}
+	for !m.BufEOF() {
+	}
+	m.ColumnMode = true
+	m.ColumnWidth = true
+	return m
+}
+
+func TestDocument_widthSampleStart(t *testing.T) {
+	t.Parallel()
+	m := widthDocument(t, "NAME   AGE\n"+strings.Repeat("web-1  3d\n", 2500))
+	m.Header = 1
+	tests := []struct {
+		lN   int
+		want int
+	}{
+		{lN: 0, want: 1},
+		{lN: 1, want: 1},
+		{lN: 1000, want: 1},
+		{lN: 1001, want: 1001},
+		{lN: 2400, want: 2001},
+	}
+	for _, tt := range tests {
+		if got := m.widthSampleStart(tt.lN); got != tt.want {
+			t.Errorf("widthSampleStart(%d) = %d, want %d", tt.lN, got, tt.want)
+		}
+	}
+}
+
+func TestDocument_updateColumnWidths(t *testing.T) {
+	t.Parallel()
+	str := "NAME   READY  AGE\n" +
+		strings.Repeat("web-1  1/1    3d\n", widthSampleLines) +
+		strings.Repeat("web-1234  1/1    3d\n", widthSampleLines)
+	m := widthDocument(t, str)
+	m.Header = 1
+	m.updateColumnWidths()
+	if want := []int{6, 13}; !reflect.DeepEqual(m.columnWidths, want) {
+		t.Fatalf("updateColumnWidths() = %v, want %v", m.columnWidths, want)
+	}
+	// The values overflow the header after the first lines.
+	m.topLN = widthSampleLines
+	m.updateColumnWidths()
+	if want := []int{9, 16}; !reflect.DeepEqual(m.columnWidths, want) {
+		t.Fatalf("updateColumnWidths() = %v, want %v", m.columnWidths, want)
+	}
+	m.topLN = 0
+	m.updateColumnWidths()
+	if want := []int{6, 13}; !reflect.DeepEqual(m.columnWidths, want) {
+		t.Fatalf("updateColumnWidths() = %v, want %v", m.columnWidths, want)
+	}
+}
+
+func TestDocument_updateColumnWidthsSection(t *testing.T) {
+	t.Parallel()
+	str := "NAME   AGE\nweb-1  3d\nweb-2  4d\n" +
+		"NAME        AGE\nweb-12345   3d\nweb-2       4d\n"
+	m := widthDocument(t, str)
+	m.setSectionDelimiter("^NAME")
+	m.updateColumnWidths()
+	if want := []int{6}; !reflect.DeepEqual(m.columnWidths, want) {
+		t.Fatalf("updateColumnWidths() = %v, want %v", m.columnWidths, want)
+	}
+	if m.columnWidthsEnd != 3 || m.columnWidthsPartial {
+		t.Errorf("end, partial = %d, %v, want 3, false", m.columnWidthsEnd, m.columnWidthsPartial)
+	}
+	m.topLN = 4
+	m.updateColumnWidths()
+	if want := []int{11}; !reflect.DeepEqual(m.columnWidths, want) {
+		t.Fatalf("updateColumnWidths() = %v, want %v", m.columnWidths, want)
+	}
+	if !m.columnWidthsPartial {
+		t.Error("columnWidthsPartial = false, want true at the end of the lines")
+	}
+}
+
+func TestDocument_updateColumnWidthsNoColumn(t *testing.T) {
+	t.Parallel()
+	m := widthDocument(t, strings.Repeat("abc\n", 10))
+	m.updateColumnWidths()
+	if !m.columnWidthsGuessed || len(m.columnWidths) != 0 {
+		t.Fatalf("guessed, widths = %v, %v, want true, []", m.columnWidthsGuessed, m.columnWidths)
+	}
+	// The empty result is not guessed again while the top line stays.
+	m.columnWidths = []int{99}
+	m.updateColumnWidths()
+	if want := []int{99}; !reflect.DeepEqual(m.columnWidths, want) {
+		t.Errorf("updateColumnWidths() = %v, want %v", m.columnWidths, want)
+	}
+	m.resetColumnWidths()
+	m.updateColumnWidths()
+	if len(m.columnWidths) != 0 {
+		t.Errorf("updateColumnWidths() after reset = %v, want []", m.columnWidths)
+	}
+}
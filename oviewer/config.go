@@ -20,6 +20,15 @@ func NewConfig() Config {
 		StyleOverLine: OVStyle{
 			Underline: true,
 		},
+		StyleJSONKey: OVStyle{
+			Foreground: "aqua",
+		},
+		StyleJSONString: OVStyle{
+			Foreground: "green",
+		},
+		StyleJSONValue: OVStyle{
+			Foreground: "yellow",
+		},
 		StyleLineNumber: OVStyle{
 			Bold: true,
 		},
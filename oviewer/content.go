@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"unicode/utf8"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/mattn/go-runewidth"
@@ -285,6 +286,127 @@ func (es *parseState) parseEscapeSequence(mainc rune) bool {
 	return false
 }
 
+// stripEscapeSequence returns the text of the string as displayed by parseString.
+// The escape sequences and carriage returns are removed,
+// and the character before the backspace is removed as overstrike.
+func stripEscapeSequence(str string) string {
+	if s, ok := stripEscapeSequenceFast(str); ok {
+		return s
+	}
+
+	buf := make([]byte, 0, len(str))
+	state := &parseState{
+		state:     ansiText,
+		style:     tcell.StyleDefault,
+		bsContent: DefaultContent,
+	}
+	// starts is the start position of each character in buf.
+	starts := make([]int, 0, len(str))
+
+	gr := uniseg.NewGraphemes(str)
+	for gr.Next() {
+		r := gr.Runes()
+		mainc := r[0]
+		if state.parseEscapeSequence(mainc) {
+			continue
+		}
+
+		switch {
+		case mainc == '\b': // BackSpace
+			if len(starts) > 0 {
+				buf = buf[:starts[len(starts)-1]]
+				starts = starts[:len(starts)-1]
+			}
+			continue
+		case mainc == '\r': // CR
+			continue
+		case runewidth.RuneWidth(mainc) == 0 && mainc >= 0x20 && len(starts) > 0:
+			// Combine with the previous character.
+		default:
+			starts = append(starts, len(buf))
+		}
+		for _, c := range r {
+			buf = utf8.AppendRune(buf, c)
+		}
+	}
+	return string(buf)
+}
+
+// stripEscapeSequenceFast strips the CSI (SGR) and OSC 8 (hyperlink) sequences byte by byte.
+// It returns false if the string needs to be parsed by graphemes,
+// such as the backspace of overstrike and the other escape sequences.
+func stripEscapeSequenceFast(str string) (string, bool) {
+	if strings.IndexByte(str, '\b') >= 0 || !utf8.ValidString(str) {
+		return "", false
+	}
+	buf := make([]byte, 0, len(str))
+	for i := 0; i < len(str); {
+		n := strings.IndexAny(str[i:], "\x1b\r\n")
+		if n < 0 {
+			buf = append(buf, str[i:]...)
+			break
+		}
+		buf = append(buf, str[i:i+n]...)
+		i += n
+		if str[i] != 0x1b {
+			i++
+			continue
+		}
+		l := escapeSequenceLen(str[i:])
+		if l < 0 {
+			return "", false
+		}
+		i += l
+	}
+	return string(buf), true
+}
+
+// escapeSequenceLen returns the length of the CSI or OSC 8 sequence at the beginning of s.
+// It returns -1 for the other sequences.
+func escapeSequenceLen(s string) int {
+	if len(s) < 2 {
+		return len(s)
+	}
+	switch s[1] {
+	case '[':
+		i := 2
+		for i < len(s) && s[i] >= 0x30 && s[i] <= 0x3f {
+			i++
+		}
+		if i == len(s) {
+			return i
+		}
+		// The final character.
+		if s[i] >= utf8.RuneSelf {
+			return -1
+		}
+		return i + 1
+	case ']':
+		if !strings.HasPrefix(s[2:], "8;") {
+			return -1
+		}
+		i := 4
+		p := strings.IndexByte(s[i:], ';')
+		if p < 0 {
+			return -1
+		}
+		i += p + 1
+		// The URL ends with BEL or ST (ESC \).
+		e := strings.IndexAny(s[i:], "\x07\x1b")
+		if e < 0 {
+			return -1
+		}
+		i += e
+		if s[i] == 0x07 {
+			return i + 1
+		}
+		if i+1 < len(s) && s[i+1] == '\\' {
+			return i + 2
+		}
+	}
+	return -1
+}
+
 // overstrike set style for overstrike.
 func (es *parseState) overstrike(m content, style tcell.Style) tcell.Style {
 	if !es.bsFlag {
@@ -889,3 +889,182 @@ func Test_widthPos_n(t *testing.T) {
 		})
 	}
 }
+
+func Test_stripEscapeSequence(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		str  string
+		want string
+	}{
+		{
+			name: "testSGR",
+			str:  "\x1b[1;31mfoo\x1b[0mbar",
+			want: "foobar",
+		},
+		{
+			name: "testOtherCSI",
+			str:  "foo\x1b[Kbar\x1b[2J",
+			want: "foobar",
+		},
+		{
+			name: "testHyperlink",
+			str:  "fo\x1b]8;;http://example.com\x1b\\ob\x1b]8;;\x1b\\ar",
+			want: "foobar",
+		},
+		{
+			name: "testHyperlinkBEL",
+			str:  "fo\x1b]8;;http://example.com\aob\x1b]8;;\aar",
+			want: "foobar",
+		},
+		{
+			name: "testOverstrike",
+			str:  "f\bfo\bo_\bb",
+			want: "fob",
+		},
+		{
+			name: "testOverstrikeWide",
+			str:  "あ\bあい",
+			want: "あい",
+		},
+		{
+			name: "testCR",
+			str:  "foo\r",
+			want: "foo",
+		},
+		{
+			name: "testCombining",
+			str:  "é\x1b[31mx",
+			want: "éx",
+		},
+		{
+			name: "testUnterminatedCSI",
+			str:  "foo\x1b[31",
+			want: "foo",
+		},
+		{
+			name: "testLastEscape",
+			str:  "foo\x1b",
+			want: "foo",
+		},
+		{
+			name: "testCharset",
+			str:  "\x1b(Bfoo",
+			want: "foo",
+		},
+		{
+			name: "testSGROverstrike",
+			str:  "\x1b[1mf\bfoo\x1b[m",
+			want: "foo",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := stripEscapeSequence(tt.str)
+			if got != tt.want {
+				t.Errorf("stripEscapeSequence() = %q, want %q", got, tt.want)
+			}
+			// It must be the same as the displayed text.
+			if str, _ := ContentsToStr(parseString(tt.str, 8)); got != str {
+				t.Errorf("stripEscapeSequence() = %q, ContentsToStr() = %q", got, str)
+			}
+		})
+	}
+}
+
+func Test_stripEscapeSequenceFast(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		str    string
+		wantOk bool
+	}{
+		{name: "testSGR", str: "\x1b[1;31mfoo\x1b[0mbar", wantOk: true},
+		{name: "testHyperlink", str: "fo\x1b]8;;http://example.com\x1b\\ob\x1b]8;;\aar", wantOk: true},
+		{name: "testCR", str: "foo\r\x1b[m", wantOk: true},
+		{name: "testOverstrike", str: "\x1b[1mf\bfoo", wantOk: false},
+		{name: "testCharset", str: "\x1b(Bfoo", wantOk: false},
+		{name: "testInvalidUTF8", str: "\x1b[mfo\xffo", wantOk: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := stripEscapeSequenceFast(tt.str)
+			if ok != tt.wantOk {
+				t.Fatalf("stripEscapeSequenceFast() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if !ok {
+				return
+			}
+			if str, _ := ContentsToStr(parseString(tt.str, 8)); got != str {
+				t.Errorf("stripEscapeSequenceFast() = %q, ContentsToStr() = %q", got, str)
+			}
+		})
+	}
+}
+
+func Test_searchHighlightPosition(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		str       string
+		word      string
+		wantStart int
+		wantEnd   int
+	}{
+		{
+			name:      "testEscape",
+			str:       "a\x1b[31mfo\x1b[0mo",
+			word:      "foo",
+			wantStart: 1,
+			wantEnd:   4,
+		},
+		{
+			name:      "testHyperlink",
+			str:       "fo\x1b]8;;http://example.com\x1b\\ob\x1b]8;;\x1b\\ar",
+			word:      "ob",
+			wantStart: 2,
+			wantEnd:   4,
+		},
+		{
+			name:      "testWide",
+			str:       "あいう",
+			word:      "い",
+			wantStart: 2,
+			wantEnd:   4,
+		},
+		{
+			name:      "testTab",
+			str:       "a\tb",
+			word:      "\t",
+			wantStart: 1,
+			wantEnd:   8,
+		},
+		{
+			name:      "testCombining",
+			str:       "xéy",
+			word:      "e",
+			wantStart: 1,
+			wantEnd:   2,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			lc := parseString(tt.str, 8)
+			str, pos := ContentsToStr(lc)
+			indexes := NewSearcher(tt.word, nil, true, false).FindAll(str)
+			if len(indexes) != 1 {
+				t.Fatalf("FindAll() = %v", indexes)
+			}
+			gotStart, gotEnd := pos.x(indexes[0][0]), pos.x(indexes[0][1])
+			if gotStart != tt.wantStart || gotEnd != tt.wantEnd {
+				t.Errorf("position = %d, %d, want %d, %d", gotStart, gotEnd, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
@@ -43,6 +43,9 @@ func (m *Document) ControlFile(file *os.File) error {
 		atomic.StoreInt32(&m.closed, 1)
 		log.Println(err)
 	}
+	if Spool && !m.seekable && m.seekPoints == nil {
+		m.spool = newSpool()
+	}
 	atomic.StoreInt32(&m.store.eof, 0)
 
 	go func() {
@@ -71,6 +74,9 @@ func (m *Document) ControlReader(r io.Reader, reload func() *bufio.Reader) error
 	m.memoryLimit = loadChunksCapacity(false)
 	m.store.setNewLoadChunks(m.memoryLimit)
 	m.seekable = false
+	if Spool {
+		m.spool = newSpool()
+	}
 	reader := bufio.NewReader(r)
 
 	go func() {
@@ -96,6 +102,48 @@ func (m *Document) ControlReader(r io.Reader, reload func() *bufio.Reader) error
 	return nil
 }
 
+// ControlSource is the controller for Source.
+// The evicted chunks of the seekable source are read again from the source.
+// It returns an error if the source cannot be opened.
+func (m *Document) ControlSource(src Source) error {
+	m.memoryLimit = loadChunksCapacity(false)
+	m.store.setNewLoadChunks(m.memoryLimit)
+	m.seekable = false
+	m.reopenable = false
+	m.source = src
+	if Spool && !src.Seekable() {
+		m.spool = newSpool()
+	}
+	r, err := src.Open()
+	if err != nil {
+		return err
+	}
+	atomic.StoreInt32(&m.closed, 0)
+	reader := bufio.NewReader(r)
+
+	go func() {
+		for sc := range m.ctlCh {
+			var err error
+			reader, err = m.controlSource(sc, reader)
+			if err != nil {
+				log.Println(sc.request, err)
+			}
+			if sc.done != nil {
+				if err != nil {
+					sc.done <- false
+				} else {
+					sc.done <- true
+				}
+				close(sc.done)
+			}
+		}
+		log.Println("close ctlCh")
+	}()
+
+	m.requestStart()
+	return nil
+}
+
 // ControlLog controls log.
 // ControlLog is only supported reload.
 func (m *Document) ControlLog() error {
@@ -128,7 +176,10 @@ func (m *Document) controlFile(sc controlSpecifier, reader *bufio.Reader) (*bufi
 		}
 		return m.continueRead(reader)
 	case requestContinue:
-		if !m.store.isContinueRead(m.memoryLimit) {
+		if m.spool != nil {
+			// Spill the old chunks and continue reading.
+			m.store.evictChunksReload(m.spool)
+		} else if !m.store.isContinueRead(m.memoryLimit) {
 			return reader, nil
 		}
 		if m.seekable && atomic.LoadInt32(&m.tmpFollow) == 0 && (m.FollowMode || m.FollowAll) {
@@ -166,10 +217,19 @@ func (m *Document) controlReader(sc controlSpecifier, reader *bufio.Reader, relo
 		// controlReader is the same for first and continue.
 		return m.continueRead(reader)
 	case requestContinue:
+		if m.spool != nil {
+			// Spill the old chunks and continue reading.
+			m.store.evictChunksReload(m.spool)
+		}
 		return m.continueRead(reader)
 	case requestLoad:
+		if m.spool != nil {
+			return m.loadReadEvictable(reader, sc.chunkNum)
+		}
 		// Since controlReader is loaded outside, it only evicts.
 		m.store.evictChunksMem(sc.chunkNum)
+	case requestSearch:
+		return m.searchRead(reader, sc.chunkNum, sc.searcher)
 	case requestReload:
 		if reload != nil {
 			log.Println("reload")
@@ -185,6 +245,41 @@ func (m *Document) controlReader(sc controlSpecifier, reader *bufio.Reader, relo
 	return reader, nil
 }
 
+// controlSource controls Source.
+// controlSource receives and executes request.
+func (m *Document) controlSource(sc controlSpecifier, reader *bufio.Reader) (*bufio.Reader, error) {
+	if atomic.LoadInt32(&m.closed) == 1 && sc.request != requestReload {
+		return reader, fmt.Errorf("%w %s", ErrAlreadyClose, sc.request)
+	}
+	switch sc.request {
+	case requestStart:
+		return m.continueRead(reader)
+	case requestContinue:
+		if m.spool != nil {
+			// Spill the old chunks and continue reading.
+			m.store.evictChunksReload(m.spool)
+		} else if !m.store.isContinueRead(m.memoryLimit) {
+			return reader, nil
+		}
+		return m.continueRead(reader)
+	case requestFollow:
+		return m.followSource(reader)
+	case requestLoad:
+		return m.loadRead(reader, sc.chunkNum)
+	case requestSearch:
+		return m.searchRead(reader, sc.chunkNum, sc.searcher)
+	case requestReload:
+		reader = m.reloadSource()
+		atomic.StoreInt32(&m.closed, 0)
+		m.requestStart()
+		return reader, nil
+	case requestClose:
+		return reader, m.closeSource()
+	default:
+		panic(fmt.Sprintf("unexpected %s", sc.request))
+	}
+}
+
 // controlLog controls log.
 // controlLog receives and executes request.
 func (m *Document) controlLog(sc controlSpecifier) {
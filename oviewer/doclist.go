@@ -136,7 +136,11 @@ func (root *Root) setDocumentNum(docNum int) {
 }
 
 // setDocument sets the Document.
+// The match count of the previous document is stopped.
 func (root *Root) setDocument(m *Document) {
+	if root.Doc != nil && root.Doc != m {
+		root.Doc.stopMatchCount()
+	}
 	root.Doc = m
 	root.ViewSync()
 }
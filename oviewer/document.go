@@ -14,7 +14,6 @@ import (
 
 	lru "github.com/hashicorp/golang-lru/v2"
 	"github.com/jwalton/gchalk"
-	"github.com/noborus/guesswidth"
 	"github.com/noborus/ov/biomap"
 )
 
@@ -24,6 +23,9 @@ const (
 	DocHelp
 	DocLog
 	DocFilter
+	DocSearchAll
+	DocSort
+	DocStats
 )
 
 type documentType int
@@ -52,6 +54,22 @@ type Document struct {
 	// store represents store management.
 	store       *store
 	followStore *store
+	// mmap is the current mapping of the file when MmapFile is enabled.
+	mmap []byte
+	// mmaps is all the mappings of the file.
+	// The previous mappings are kept until close or reload because lines may still refer to them.
+	mmaps [][]byte
+	// spool is the temporary file of the evicted chunks of the non-seekable document.
+	// It is nil if Spool is disabled.
+	spool *spool
+	// source is the input source when the document is controlled by ControlSource.
+	source Source
+	// seekPoints is the seek points of the compressed regular file.
+	// It is nil if the evicted chunks cannot be reloaded.
+	seekPoints *seekPoints
+	// matchCount is the count of the lines that match the current search.
+	// It is accessed from the UI and the counting goroutines.
+	matchCount atomic.Pointer[matchCounter]
 
 	// fileName is the file name to display.
 	FileName string
@@ -64,6 +82,34 @@ type Document struct {
 	marked []int
 	// columnWidths is a slice of column widths.
 	columnWidths []int
+	// columnLastWidth is the width of the last column of ColumnWidth.
+	columnLastWidth int
+	// columnWidthsGuessed is true if columnWidths has been guessed.
+	// The guess may find no columns, so columnWidths can be empty.
+	columnWidthsGuessed bool
+	// columnWidthsTop is the top line when the column widths were last checked.
+	columnWidthsTop int
+	// columnWidthsStart is the first line of the lines that guessed columnWidths.
+	columnWidthsStart int
+	// columnWidthsEnd is the end of the lines that guessed columnWidths.
+	columnWidthsEnd int
+	// columnWidthsPartial is true if the lines that guessed columnWidths
+	// were cut by the end of the loaded lines.
+	columnWidthsPartial bool
+	// columnDetected is true if the column is detected by ColumnAuto.
+	// It is read by the update timer, so it is atomic.
+	columnDetected atomic.Bool
+	// logfmtKeys is a slice of the keys of the logfmt columns.
+	logfmtKeys []string
+	// logfmtWidths is a slice of the widths of the logfmt columns.
+	logfmtWidths []int
+	// alignWidths is a slice of the widths of the aligned columns.
+	alignWidths []int
+	// alignComputed is true if alignWidths has been computed.
+	// No line may have the delimiter, so alignWidths can be empty.
+	alignComputed bool
+	// alignWidthsEnd is the end of the lines that computed alignWidths.
+	alignWidthsEnd int
 
 	// status is the display status of the document.
 	general
@@ -73,6 +119,8 @@ type Document struct {
 
 	// currentChunk represents the current chunk number.
 	currentChunk int
+	// indexChunks is the number of chunks saved in (or restored from) the index cache.
+	indexChunks int
 
 	// headerLen is the actual header length when wrapped.
 	headerLen int
@@ -173,6 +221,8 @@ type chunk struct {
 	lines [][]byte
 	// start is the first position of the number of bytes read.
 	start int64
+	// mapped is true if the lines are slices of the mapped file.
+	mapped bool
 }
 
 // LineC is one line of information.
@@ -296,7 +346,12 @@ func (m *Document) Line(n int) ([]byte, error) {
 		m.requestLoad(chunkNum)
 	}
 
-	return s.GetChunkLine(chunkNum, cn)
+	line, err := s.GetChunkLine(chunkNum, cn)
+	if errors.Is(err, ErrTruncated) {
+		// Release the mapping so that the file is read again without mapping.
+		m.checkTruncated()
+	}
+	return line, err
 }
 
 // GetChunkLine returns one line from buffer.
@@ -311,7 +366,15 @@ func (s *store) GetChunkLine(chunkNum int, cn int) ([]byte, error) {
 	if cn >= len(chunk.lines) {
 		return nil, fmt.Errorf("over line (%d:%d) %w", chunkNum, cn, ErrOutOfRange)
 	}
-	return bytes.TrimSuffix(chunk.lines[cn], []byte("\n")), nil
+	line := chunk.lines[cn]
+	if chunk.mapped {
+		b, err := copyMapped(line)
+		if err != nil {
+			return nil, err
+		}
+		line = b
+	}
+	return bytes.TrimSuffix(line, []byte("\n")), nil
 }
 
 // GetLine returns one line from buffer.
@@ -400,7 +463,36 @@ func (m *Document) contents(lN int, tabWidth int) (contents, error) {
 	}
 
 	str, err := m.LineStr(lN)
-	return parseString(str, tabWidth), err
+	if m.JSONMode && m.WrapMode && lN >= m.firstLine() {
+		if lc, ok := jsonContents(str, tabWidth); ok {
+			return lc, err
+		}
+	}
+	if m.ColumnLogfmt && lN >= m.firstLine() {
+		if lc, ok := logfmtContents(str, m.logfmtKeys, m.logfmtWidths, tabWidth); ok {
+			return lc, err
+		}
+	}
+	ordered := m.columnOrdered() && lN >= m.SkipLines
+	aligned := m.ColumnAlign && !m.ColumnWidth && !m.ColumnLogfmt && lN >= m.SkipLines
+	if (ordered && !m.ColumnWidth) || aligned {
+		// The tabs are expanded after the columns are split.
+		lc := parseString(str, 1)
+		if ordered {
+			lc = m.orderContents(lc)
+		}
+		if aligned {
+			if alc, ok := m.alignContents(lc, tabWidth); ok {
+				return alc, err
+			}
+		}
+		return expandTabs(lc, tabWidth), err
+	}
+	lc := parseString(str, tabWidth)
+	if ordered {
+		lc = m.orderWidthContents(lc)
+	}
+	return lc, err
 }
 
 // getLineC returns contents from line number and tabWidth.
@@ -473,6 +565,8 @@ func (m *Document) regexpCompile() {
 func (m *Document) setDelimiter(delm string) {
 	m.ColumnDelimiter = delm
 	m.ColumnDelimiterReg = condRegexpCompile(delm)
+	m.clearAlignWidths()
+	m.clearOrderCache()
 }
 
 // setSectionDelimiter sets the document section delimiter.
@@ -486,21 +580,3 @@ func (m *Document) setMultiColorWords(words []string) {
 	m.MultiColorWords = words
 	m.multiColorRegexps = multiRegexpCompile(words)
 }
-
-// setColumnWidths sets the column widths.
-// Guess the width of the columns using the first 1000 lines (maximum) and the headers.
-func (m *Document) setColumnWidths() {
-	if m.BufEndNum() == 0 {
-		return
-	}
-
-	header := m.Header - 1
-	header = max(header, 0)
-	tl := min(1000, len(m.store.chunks[0].lines))
-	lines := m.store.chunks[0].lines[m.SkipLines:tl]
-	buf := make([]string, len(lines))
-	for n, line := range lines {
-		buf[n] = string(line)
-	}
-	m.columnWidths = guesswidth.Positions(buf, header, 2)
-}
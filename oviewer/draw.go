@@ -27,8 +27,14 @@ func (root *Root) draw() {
 		return
 	}
 
-	if m.ColumnWidth && len(m.columnWidths) == 0 {
-		m.setColumnWidths()
+	if m.ColumnWidth {
+		m.updateColumnWidths()
+	}
+	if m.ColumnLogfmt && len(m.logfmtKeys) == 0 {
+		m.setLogfmtColumns()
+	}
+	if m.ColumnAlign && m.needAlignWidths() {
+		m.setAlignWidths()
 	}
 
 	// Header
@@ -262,6 +268,16 @@ func (root *Root) drawWrapLine(y int, lX int, lN int, lc contents) (int, int) {
 			break
 		}
 		content := lc[lX+x]
+		if content.mainc == '\n' {
+			// Line break of JSON mode.
+			root.clearEOL(root.scr.startX+x, y)
+			lX += x + 1
+			if lX >= len(lc) {
+				lX = 0
+				lN++
+			}
+			break
+		}
 		if x+root.scr.startX+content.width > root.scr.vWidth {
 			// Right edge.
 			root.clearEOL(root.scr.startX+x, y)
@@ -277,15 +293,24 @@ func (root *Root) drawWrapLine(y int, lX int, lN int, lc contents) (int, int) {
 // drawNoWrapLine draws contents without wrapping and returns the next drawing position.
 func (root *Root) drawNoWrapLine(y int, startX int, lN int, lc contents) (int, int) {
 	startX = max(startX, root.minStartX)
+	// The pinned columns are drawn at the left edge when scrolling.
+	pin := 0
+	if startX > 0 {
+		pin = root.Doc.pinnedWidth(lc)
+	}
 	for x := 0; root.scr.startX+x < root.scr.vWidth; x++ {
-		if startX+x >= len(lc) {
+		n := startX + x
+		if x < pin {
+			n = x
+		}
+		if n >= len(lc) {
 			// EOL
 			root.clearEOL(root.scr.startX+x, y)
 			break
 		}
 		content := DefaultContent
-		if startX+x >= 0 {
-			content = lc[startX+x]
+		if n >= 0 {
+			content = lc[n]
 		}
 		root.Screen.SetContent(root.scr.startX+x, y, content.mainc, content.combc, content.style)
 	}
@@ -374,6 +399,10 @@ func (root *Root) drawLineNumber(lN int, y int, valid bool) {
 
 // columnHighlight applies the style of the column highlight.
 func (root *Root) columnHighlight(line LineC) {
+	if root.Doc.ColumnLogfmt {
+		root.columnLogfmtHighlight(line)
+		return
+	}
 	if root.Doc.ColumnWidth {
 		root.columnWidthHighlight(line)
 		return
@@ -384,7 +413,7 @@ func (root *Root) columnHighlight(line LineC) {
 // columnHighlight applies the style of the column highlight.
 func (root *Root) columnDelimiterHighlight(line LineC) {
 	m := root.Doc
-	indexes := allIndex(line.str, m.ColumnDelimiter, m.ColumnDelimiterReg)
+	indexes := m.delimiterIndex(line.str)
 	if len(indexes) == 0 {
 		return
 	}
@@ -431,26 +460,9 @@ func (root *Root) columnDelimiterHighlight(line LineC) {
 
 func (root *Root) columnWidthHighlight(line LineC) {
 	m := root.Doc
-	indexes := m.columnWidths
-	if len(indexes) == 0 {
-		return
-	}
-	iStart, iEnd := 0, 0
 	numC := len(root.StyleColumnRainbow)
-	for c := 0; c < len(indexes)+1; c++ {
-		switch {
-		case c == 0:
-			iStart = 0
-			iEnd = findBounds(line.lc, indexes[0]-1, indexes, c)
-		case c < len(indexes):
-			iStart = iEnd + 1
-			iEnd = findBounds(line.lc, indexes[c], indexes, c)
-		case c == len(indexes):
-			iStart = iEnd + 1
-			iEnd = len(line.str)
-		}
-		iEnd = min(iEnd, len(line.lc))
-
+	for c, r := range widthColumnRanges(line.lc, m.widthPositions()) {
+		iStart, iEnd := r[0], r[1]
 		if m.ColumnRainbow {
 			RangeStyle(line.lc, iStart, iEnd, root.StyleColumnRainbow[c%numC])
 		}
@@ -585,6 +597,9 @@ func (root *Root) normalLeftStatus() (contents, int) {
 		caption = root.Doc.Caption
 	} else if root.Config.Prompt.Normal.ShowFilename {
 		caption = root.Doc.FileName
+		if root.Doc.CFormat != UNCOMPRESSED {
+			caption += "(" + root.Doc.CFormat.String() + ")"
+		}
 	}
 
 	leftStatus := fmt.Sprintf("%s%s%s:%s", number, modeStatus, caption, root.message)
@@ -612,14 +627,26 @@ func (root *Root) inputOpts() string {
 
 	// The current search mode.
 	mode := root.input.Event.Mode()
-	if mode == Search || mode == Backsearch || mode == Filter {
+	if mode == JSONFilter && root.Doc.nonMatch {
+		opts += "Non-match"
+	}
+	if mode == Search || mode == Backsearch || mode == Filter || mode == SearchAll {
 		if root.Doc.nonMatch {
 			opts += "Non-match"
 		}
 		if root.Config.RegexpSearch {
 			opts += "(R)"
 		}
-		if mode != Filter {
+		if root.Config.BooleanSearch {
+			opts += "(B)"
+		}
+		if root.Config.FuzzySearch {
+			opts += "(F)"
+		}
+		if root.Config.ColumnSearch && root.Doc.ColumnMode {
+			opts += "(Col)"
+		}
+		if mode != Filter && mode != SearchAll {
 			if root.Config.Incsearch {
 				opts += "(I)"
 			}
@@ -644,6 +671,9 @@ func (root *Root) rightStatus() contents {
 	if atomic.LoadInt32(&root.Doc.tmpFollow) == 1 {
 		str = fmt.Sprintf("(?/%d%s)", root.Doc.storeEndNum(), next)
 	}
+	if mc := root.Doc.matchCount.Load(); mc != nil && root.searcher != nil && mc.key == matchCounterKey(root.searcher, root.Doc.nonMatch) {
+		str = mc.String() + " " + str
+	}
 
 	rightContents := StrToContents(str, -1)
 
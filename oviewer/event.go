@@ -64,6 +64,14 @@ func (root *Root) eventLoop(ctx context.Context, quitChan chan<- struct{}) {
 			root.searchGo(ev.value)
 		case *eventInputFilter:
 			root.filter(ctx)
+		case *eventInputSearchAll:
+			root.searchAll(ctx)
+		case *eventInputJSONFilter:
+			root.jsonFilter(ctx)
+		case *eventInputSort:
+			root.sortColumn(ctx)
+		case *eventColumnStats:
+			root.columnStats(ctx)
 		case *eventGoto:
 			root.goLine(ev.value)
 		case *eventHeader:
@@ -90,6 +98,12 @@ func (root *Root) eventLoop(ctx context.Context, quitChan chan<- struct{}) {
 			root.saveBuffer(ev.value)
 		case *eventSectionNum:
 			root.setSectionNum(ev.value)
+		case *eventColumnKey:
+			root.setColumnKey(ev.value)
+		case *eventColumnOrder:
+			root.setColumnOrder(ev.value)
+		case *eventColumnPin:
+			root.setColumnPin(ev.value)
 
 		// tcell events
 		case *tcell.EventResize:
@@ -217,14 +231,32 @@ type eventUpdateEndNum struct {
 
 // regularUpdate fires an eventUpdateEndNum event when an update is required.
 func (root *Root) regularUpdate() {
+	root.requestFollowSource()
 	root.sendUpdateEndNum()
 }
 
+// requestFollowSource requests to read the part of the seekable source that has grown in follow mode.
+// The request is skipped if the document is busy.
+func (root *Root) requestFollowSource() {
+	m := root.Doc
+	if m.source == nil || !m.source.Seekable() {
+		return
+	}
+	if !m.FollowMode && !m.FollowAll {
+		return
+	}
+	select {
+	case m.ctlCh <- controlSpecifier{request: requestFollow}:
+	default:
+	}
+}
+
 func (root *Root) sendUpdateEndNum() {
 	if !root.hasDocChanged() {
 		return
 	}
-	if !root.Config.Prompt.Normal.ProcessOfCount && !root.Doc.BufEOF() {
+	// ColumnAuto waits for the update to detect the column.
+	if !root.Config.Prompt.Normal.ProcessOfCount && !root.Doc.BufEOF() && !root.Doc.columnAutoPending() {
 		return
 	}
 	ev := &eventUpdateEndNum{}
@@ -48,6 +48,22 @@ func ExampleNewOviewer() {
 	}
 }
 
+func ExampleNewSourceDocument() {
+	b := []byte(strings.Repeat("south\n", 99))
+	doc, err := oviewer.NewSourceDocument(oviewer.NewBytesSource(b))
+	if err != nil {
+		panic(err)
+	}
+
+	ov, err := oviewer.NewOviewer(doc)
+	if err != nil {
+		panic(err)
+	}
+	if err := ov.Run(); err != nil {
+		panic(err)
+	}
+}
+
 func ExampleExecCommand() {
 	command := exec.Command("ls", "-alF")
 	ov, err := oviewer.ExecCommand(command)
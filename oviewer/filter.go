@@ -26,7 +26,38 @@ func (root *Root) filter(ctx context.Context) {
 		}
 		return
 	}
-	word := root.searcher.String()
+	root.filterDocument(ctx, searcher)
+}
+
+// JSONFilter fires the JSON filter event.
+func (root *Root) JSONFilter(expr string, nonMatch bool) {
+	root.Doc.nonMatch = nonMatch
+	root.input.value = expr
+	ev := &eventInputJSONFilter{
+		value: expr,
+	}
+	root.postEvent(ev)
+}
+
+// jsonFilter filters the JSON lines of the document by the expression of the input value.
+func (root *Root) jsonFilter(ctx context.Context) {
+	if root.input.value == "" {
+		return
+	}
+	searcher, err := NewJSONSearcher(root.input.value)
+	if err != nil {
+		root.setMessageLog(err.Error())
+		return
+	}
+	// Replace the previous pattern so that it is not highlighted in the filter document.
+	root.searcher = searcher
+	root.Doc.startMatchCount(searcher)
+	root.filterDocument(ctx, searcher)
+}
+
+// filterDocument creates a new document with the lines that match the searcher.
+func (root *Root) filterDocument(ctx context.Context, searcher Searcher) {
+	word := searcher.String()
 	if root.Doc.nonMatch {
 		word = fmt.Sprintf("!%s", word)
 	}
@@ -80,7 +111,7 @@ func (m *Document) searchWriter(ctx context.Context, searcher Searcher, filterDo
 			break
 		}
 		// Found
-		line, err := m.Line(lineNum)
+		line, err := m.matchedLine(lineNum)
 		if err != nil {
 			break
 		}
@@ -97,6 +128,19 @@ func (m *Document) searchWriter(ctx context.Context, searcher Searcher, filterDo
 	}
 }
 
+// matchedLine returns the line found by searchLine.
+// The line found in the file by the parallel search may not be in memory,
+// so the chunk is loaded and waited for before reading.
+func (m *Document) matchedLine(lineNum int) ([]byte, error) {
+	chunkNum, _ := chunkLineNum(lineNum)
+	// The searcher that matches all lines loads the chunk.
+	loader := NewSearcher("", nil, true, false)
+	if !m.isLoadedChunk(chunkNum) && !m.storageSearch(loader, chunkNum) {
+		return nil, fmt.Errorf("chunk %d %w", chunkNum, ErrNotLoaded)
+	}
+	return m.Line(lineNum)
+}
+
 // closeAllFilter closes all filter documents.
 func (root *Root) closeAllFilter() {
 	root.closeAllDocument(DocFilter)
@@ -0,0 +1,247 @@
+package oviewer
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+)
+
+// indexVersion is the version of the index cache format.
+const indexVersion = 1
+
+// indexFingerprintSize is the number of bytes before the end of the indexed range
+// used to verify that the indexed part of the file has not changed.
+const indexFingerprintSize = 4096
+
+// lineIndex is the line index of a seekable file stored on disk.
+// It holds the start offsets of the complete chunks,
+// so that the chunks can be restored without counting the lines again.
+type lineIndex struct {
+	// Path is the absolute path of the file.
+	Path string
+	// Fingerprint is the hash of the bytes just before Size.
+	Fingerprint []byte
+	// Starts is the start offset of each chunk.
+	// The last element is the end of the last complete chunk.
+	Starts []int64
+	// Version is the version of the index format.
+	Version int
+	// ChunkSize is the ChunkSize at the time of indexing.
+	ChunkSize int
+	// Inode is the inode number of the file.
+	Inode uint64
+	// FileSize is the file size at the time of indexing.
+	FileSize int64
+	// ModTime is the modification time(UnixNano) at the time of indexing.
+	ModTime int64
+}
+
+// indexCacheDir returns the directory where the index cache is stored.
+func indexCacheDir() (string, error) {
+	if IndexCacheDir != "" {
+		return IndexCacheDir, nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "ov", "index"), nil
+}
+
+// indexFileName returns the file name of the index cache.
+// The index is identified by the path and the inode of the file.
+func indexFileName(dir string, path string, inode uint64) string {
+	sum := sha256.Sum256([]byte(path + "\x00" + strconv.FormatUint(inode, 10)))
+	return filepath.Join(dir, hex.EncodeToString(sum[:]))
+}
+
+// useIndex returns true if the document can use the index cache.
+func (m *Document) useIndex() bool {
+	return IndexCache && m.seekable && m.CFormat == UNCOMPRESSED && m.FileName != "" && m.file != nil
+}
+
+// indexKey returns the absolute path and file information used as the index key.
+func (m *Document) indexKey() (string, os.FileInfo, error) {
+	fi, err := m.file.Stat()
+	if err != nil {
+		return "", nil, err
+	}
+	path, err := filepath.Abs(m.FileName)
+	if err != nil {
+		return "", nil, err
+	}
+	return path, fi, nil
+}
+
+// applyIndex restores the reserved chunks from the index cache.
+// It is called after the first chunk has been read,
+// and only the part after the indexed range is read afterwards.
+func (m *Document) applyIndex() {
+	path, fi, err := m.indexKey()
+	if err != nil {
+		return
+	}
+	idx, err := readIndex(path, fileInode(fi))
+	if err != nil {
+		return
+	}
+	if !m.validIndex(idx, fi) {
+		return
+	}
+
+	s := m.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// The first chunk has already been read, so it must match.
+	if len(s.chunks) != 1 || idx.Starts[1] != s.size {
+		return
+	}
+	complete := len(idx.Starts) - 1
+	for _, start := range idx.Starts[1:complete] {
+		// Reserved chunks are loaded later by loadChunk.
+		s.chunks = append(s.chunks, &chunk{start: start})
+	}
+	s.size = idx.Starts[complete]
+	s.offset = s.size
+	atomic.StoreInt32(&s.endNum, int32(complete*ChunkSize))
+	atomic.StoreInt32(&s.changed, 1)
+	m.indexChunks = complete
+	log.Printf("index cache: %s %d lines restored", m.FileName, complete*ChunkSize)
+}
+
+// validIndex returns true if the index can be applied to the current file.
+func (m *Document) validIndex(idx lineIndex, fi os.FileInfo) bool {
+	if idx.Version != indexVersion || idx.ChunkSize != ChunkSize {
+		return false
+	}
+	// Needs more than the first chunk to be useful.
+	if len(idx.Starts) < 3 {
+		return false
+	}
+	size := idx.Starts[len(idx.Starts)-1]
+	if fi.Size() < size {
+		return false
+	}
+	if fi.Size() == idx.FileSize && fi.ModTime().UnixNano() == idx.ModTime {
+		return true
+	}
+	// The file has been changed (e.g. appended), check the indexed part.
+	fp, err := fingerprint(m.file, size)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(fp, idx.Fingerprint)
+}
+
+// saveIndex saves the line index of the document in the index cache.
+// Only complete chunks are saved, the rest is read again next time.
+func (m *Document) saveIndex() {
+	s := m.store
+	s.mu.RLock()
+	starts := make([]int64, 0, len(s.chunks)+1)
+	for _, chunk := range s.chunks {
+		starts = append(starts, chunk.start)
+	}
+	endNum := int(atomic.LoadInt32(&s.endNum))
+	if endNum == len(s.chunks)*ChunkSize && atomic.LoadInt32(&s.noNewlineEOF) == 0 {
+		starts = append(starts, s.size)
+	}
+	s.mu.RUnlock()
+
+	complete := len(starts) - 1
+	if complete < 2 || complete == m.indexChunks {
+		return
+	}
+
+	path, fi, err := m.indexKey()
+	if err != nil {
+		return
+	}
+	fp, err := fingerprint(m.file, starts[complete])
+	if err != nil {
+		log.Printf("index cache: %s", err)
+		return
+	}
+	idx := lineIndex{
+		Version:     indexVersion,
+		Path:        path,
+		Inode:       fileInode(fi),
+		FileSize:    fi.Size(),
+		ModTime:     fi.ModTime().UnixNano(),
+		ChunkSize:   ChunkSize,
+		Starts:      starts,
+		Fingerprint: fp,
+	}
+	if err := writeIndex(idx); err != nil {
+		log.Printf("index cache: %s", err)
+		return
+	}
+	m.indexChunks = complete
+}
+
+// fingerprint returns the hash of the bytes just before the end.
+func fingerprint(r io.ReaderAt, end int64) ([]byte, error) {
+	start := max(0, end-indexFingerprintSize)
+	buf := make([]byte, end-start)
+	if _, err := r.ReadAt(buf, start); err != nil {
+		return nil, fmt.Errorf("fingerprint: %w", err)
+	}
+	sum := sha256.Sum256(buf)
+	return sum[:], nil
+}
+
+// readIndex reads the index cache of the file.
+func readIndex(path string, inode uint64) (lineIndex, error) {
+	var idx lineIndex
+	dir, err := indexCacheDir()
+	if err != nil {
+		return idx, err
+	}
+	f, err := os.Open(indexFileName(dir, path, inode))
+	if err != nil {
+		return idx, err
+	}
+	defer f.Close()
+
+	if err := gob.NewDecoder(f).Decode(&idx); err != nil {
+		return idx, fmt.Errorf("decode index: %w", err)
+	}
+	if idx.Path != path || idx.Inode != inode {
+		return idx, ErrNotFound
+	}
+	return idx, nil
+}
+
+// writeIndex writes the index cache of the file.
+// It is written to a temporary file and renamed so that it is not read halfway.
+func writeIndex(idx lineIndex) error {
+	dir, err := indexCacheDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, "tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := gob.NewEncoder(tmp).Encode(idx); err != nil {
+		tmp.Close()
+		return fmt.Errorf("encode index: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), indexFileName(dir, idx.Path, idx.Inode))
+}
@@ -0,0 +1,156 @@
+package oviewer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeLines writes n numbered lines to a new file in dir.
+func writeLines(t *testing.T, dir string, n int) string {
+	t.Helper()
+	var b strings.Builder
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(&b, "%d\n", i)
+	}
+	fileName := filepath.Join(dir, "lines.txt")
+	if err := os.WriteFile(fileName, []byte(b.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return fileName
+}
+
+// appendLines appends numbered lines from start to end to the file.
+func appendLines(t *testing.T, fileName string, start int, end int) {
+	t.Helper()
+	f, err := os.OpenFile(fileName, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	for i := start; i <= end; i++ {
+		fmt.Fprintf(f, "%d\n", i)
+	}
+}
+
+func openEOF(t *testing.T, fileName string) *Document {
+	t.Helper()
+	m, err := OpenDocument(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for !m.BufEOF() {
+	}
+	return m
+}
+
+func chunkStarts(m *Document) []int64 {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+	starts := make([]int64, 0, len(m.store.chunks))
+	for _, chunk := range m.store.chunks {
+		starts = append(starts, chunk.start)
+	}
+	return starts
+}
+
+func TestDocument_indexCache(t *testing.T) {
+	IndexCache = true
+	IndexCacheDir = t.TempDir()
+	defer func() {
+		IndexCache = false
+		IndexCacheDir = ""
+	}()
+
+	tests := []struct {
+		name      string
+		lines     int
+		appendN   int
+		wantIndex bool
+	}{
+		{
+			name:      "testSmall",
+			lines:     100,
+			wantIndex: false,
+		},
+		{
+			name:      "testLarge",
+			lines:     35000,
+			wantIndex: true,
+		},
+		{
+			name:      "testAppend",
+			lines:     35000,
+			appendN:   12000,
+			wantIndex: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileName := writeLines(t, t.TempDir(), tt.lines)
+			m := openEOF(t, fileName)
+			if (m.indexChunks > 0) != tt.wantIndex {
+				t.Fatalf("index saved = %v, want %v", m.indexChunks > 0, tt.wantIndex)
+			}
+			if tt.wantIndex {
+				path, fi, err := m.indexKey()
+				if err != nil {
+					t.Fatal(err)
+				}
+				idx, err := readIndex(path, fileInode(fi))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(idx.Starts[:len(idx.Starts)-1], chunkStarts(m)[:len(idx.Starts)-1]) {
+					t.Errorf("index starts = %v, want %v", idx.Starts, chunkStarts(m))
+				}
+			}
+			if tt.appendN > 0 {
+				appendLines(t, fileName, tt.lines+1, tt.lines+tt.appendN)
+			}
+			want := openEOF(t, fileName)
+
+			IndexCache = false
+			noIndex := openEOF(t, fileName)
+			IndexCache = true
+
+			if want.BufEndNum() != noIndex.BufEndNum() {
+				t.Errorf("BufEndNum() = %v, want %v", want.BufEndNum(), noIndex.BufEndNum())
+			}
+			if !reflect.DeepEqual(chunkStarts(want), chunkStarts(noIndex)) {
+				t.Errorf("chunk starts = %v, want %v", chunkStarts(want), chunkStarts(noIndex))
+			}
+			lastLine := tt.lines + tt.appendN
+			chunkNum, cn := chunkLineNum(lastLine - 1)
+			sc := controlSpecifier{
+				request:  requestLoad,
+				chunkNum: chunkNum,
+				done:     make(chan bool),
+			}
+			want.ctlCh <- sc
+			<-sc.done
+			got, err := want.store.GetChunkLine(chunkNum, cn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != fmt.Sprint(lastLine) {
+				t.Errorf("GetChunkLine() = %v, want %v", string(got), lastLine)
+			}
+		})
+	}
+}
+
+func Test_indexFileName(t *testing.T) {
+	t.Parallel()
+	a := indexFileName("dir", "/tmp/a.log", 1)
+	b := indexFileName("dir", "/tmp/a.log", 2)
+	if a == b {
+		t.Errorf("indexFileName() should be different by inode %v", a)
+	}
+	if filepath.Dir(a) != "dir" {
+		t.Errorf("indexFileName() = %v, want in dir", a)
+	}
+}
@@ -31,6 +31,12 @@ const (
 	JumpTarget                 // JumpTarget is the position to display the search results.
 	SaveBuffer                 // SaveBuffer is the save buffer.
 	SectionNum                 // SectionNum is the section number.
+	SearchAll                  // SearchAll is the search all input mode.
+	JSONFilter                 // JSONFilter is the JSON filter input mode.
+	ColumnKey                  // ColumnKey is the logfmt column key input mode.
+	Sort                       // Sort is the sort type input mode.
+	ColumnOrder                // ColumnOrder is the column order input mode.
+	ColumnPin                  // ColumnPin is the number of pinned columns input mode.
 )
 
 // Input represents the status of various inputs.
@@ -52,6 +58,10 @@ type Input struct {
 	MultiColorCandidate   *candidate
 	JumpTargetCandidate   *candidate
 	SaveBufferCandidate   *candidate
+	JSONFilterCandidate   *candidate
+	ColumnKeyCandidate    *candidate
+	SortCandidate         *candidate
+	ColumnOrderCandidate  *candidate
 
 	value   string
 	cursorX int
@@ -73,6 +83,10 @@ func NewInput() *Input {
 	i.MultiColorCandidate = multiColorCandidate()
 	i.JumpTargetCandidate = jumpTargetCandidate()
 	i.SaveBufferCandidate = saveBufferCandidate()
+	i.JSONFilterCandidate = jsonFilterCandidate()
+	i.ColumnKeyCandidate = columnKeyCandidate()
+	i.SortCandidate = sortCandidate()
+	i.ColumnOrderCandidate = columnOrderCandidate()
 
 	i.Event = &eventNormal{}
 	return &i
@@ -168,17 +182,21 @@ func (input *Input) keyEvent(evKey *tcell.EventKey) bool {
 		input.cursorX += 2
 		input.value += string(runes[pos:])
 	case tcell.KeyRune:
-		pos := countToCursor(input.value, input.cursorX+1)
-		runes := []rune(input.value)
-		input.value = string(runes[:pos])
-		r := evKey.Rune()
-		input.value += string(r)
-		input.value += string(runes[pos:])
-		input.cursorX += runewidth.RuneWidth(r)
+		input.insertRune(evKey.Rune())
 	}
 	return false
 }
 
+// insertRune inserts a rune at the cursor position.
+func (input *Input) insertRune(r rune) {
+	pos := countToCursor(input.value, input.cursorX+1)
+	runes := []rune(input.value)
+	input.value = string(runes[:pos])
+	input.value += string(r)
+	input.value += string(runes[pos:])
+	input.cursorX += runewidth.RuneWidth(r)
+}
+
 // inputCaseSensitive toggles case sensitivity.
 func (root *Root) inputCaseSensitive() {
 	root.Config.CaseSensitive = !root.Config.CaseSensitive
@@ -205,7 +223,38 @@ func (root *Root) inputRegexpSearch() {
 	root.Config.RegexpSearch = !root.Config.RegexpSearch
 }
 
+// inputBooleanSearch toggles boolean search.
+// Fuzzy search is disabled, because they cannot be used together.
+func (root *Root) inputBooleanSearch() {
+	root.Config.BooleanSearch = !root.Config.BooleanSearch
+	if root.Config.BooleanSearch {
+		root.Config.FuzzySearch = false
+	}
+}
+
+// inputFuzzySearch toggles fuzzy search.
+// Boolean search is disabled, because they cannot be used together.
+func (root *Root) inputFuzzySearch() {
+	root.Config.FuzzySearch = !root.Config.FuzzySearch
+	if root.Config.FuzzySearch {
+		root.Config.BooleanSearch = false
+	}
+}
+
+// inputColumnSearch toggles column search.
+func (root *Root) inputColumnSearch() {
+	root.Config.ColumnSearch = !root.Config.ColumnSearch
+}
+
+// inputNonMatch toggles non-match.
+// In boolean search and JSON filter, "!" is inserted as the NOT operator
+// except at the beginning of the input.
 func (root *Root) inputNonMatch() {
+	isNot := root.Config.BooleanSearch || root.input.Event.Mode() == JSONFilter
+	if isNot && root.input.value != "" {
+		root.input.insertRune(booleanNot)
+		return
+	}
 	root.Doc.nonMatch = !root.Doc.nonMatch
 }
 
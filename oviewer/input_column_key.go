@@ -0,0 +1,66 @@
+package oviewer
+
+import (
+	"github.com/gdamore/tcell/v2"
+)
+
+// setColumnKeyMode sets the inputMode to ColumnKey.
+func (root *Root) setColumnKeyMode() {
+	input := root.input
+	input.value = ""
+	input.cursorX = 0
+
+	clist := input.ColumnKeyCandidate
+	clist.mux.Lock()
+	clist.list = append([]string{}, root.Doc.logfmtKeys...)
+	clist.p = 0
+	clist.mux.Unlock()
+
+	input.Event = newColumnKeyEvent(input.ColumnKeyCandidate)
+}
+
+// columnKeyCandidate returns the candidate to set to default.
+func columnKeyCandidate() *candidate {
+	return &candidate{
+		list: []string{},
+	}
+}
+
+// eventColumnKey represents the column key input mode.
+type eventColumnKey struct {
+	tcell.EventTime
+	clist *candidate
+	value string
+}
+
+// newColumnKeyEvent returns columnKeyEvent.
+func newColumnKeyEvent(clist *candidate) *eventColumnKey {
+	return &eventColumnKey{clist: clist}
+}
+
+// Mode returns InputMode.
+func (*eventColumnKey) Mode() InputMode {
+	return ColumnKey
+}
+
+// Prompt returns the prompt string in the input field.
+func (*eventColumnKey) Prompt() string {
+	return "Column key:"
+}
+
+// Confirm returns the event when the input is confirmed.
+func (e *eventColumnKey) Confirm(str string) tcell.Event {
+	e.value = str
+	e.SetEventNow()
+	return e
+}
+
+// Up returns strings when the up key is pressed during input.
+func (e *eventColumnKey) Up(_ string) string {
+	return e.clist.up()
+}
+
+// Down returns strings when the down key is pressed during input.
+func (e *eventColumnKey) Down(_ string) string {
+	return e.clist.down()
+}
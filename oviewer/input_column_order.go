@@ -0,0 +1,63 @@
+package oviewer
+
+import (
+	"github.com/gdamore/tcell/v2"
+)
+
+// setColumnOrderMode sets the inputMode to ColumnOrder.
+func (root *Root) setColumnOrderMode() {
+	input := root.input
+	input.value = ""
+	input.cursorX = 0
+
+	input.ColumnOrderCandidate.toLast(columnOrderString(root.Doc.ColumnOrder))
+
+	input.Event = newColumnOrderEvent(input.ColumnOrderCandidate)
+}
+
+// columnOrderCandidate returns the candidate to set to default.
+func columnOrderCandidate() *candidate {
+	return &candidate{
+		list: []string{},
+	}
+}
+
+// eventColumnOrder represents the column order input mode.
+type eventColumnOrder struct {
+	tcell.EventTime
+	clist *candidate
+	value string
+}
+
+// newColumnOrderEvent returns columnOrderEvent.
+func newColumnOrderEvent(clist *candidate) *eventColumnOrder {
+	return &eventColumnOrder{clist: clist}
+}
+
+// Mode returns InputMode.
+func (*eventColumnOrder) Mode() InputMode {
+	return ColumnOrder
+}
+
+// Prompt returns the prompt string in the input field.
+func (*eventColumnOrder) Prompt() string {
+	return "Column order:"
+}
+
+// Confirm returns the event when the input is confirmed.
+func (e *eventColumnOrder) Confirm(str string) tcell.Event {
+	e.value = str
+	e.clist.toLast(str)
+	e.SetEventNow()
+	return e
+}
+
+// Up returns strings when the up key is pressed during input.
+func (e *eventColumnOrder) Up(_ string) string {
+	return e.clist.up()
+}
+
+// Down returns strings when the down key is pressed during input.
+func (e *eventColumnOrder) Down(_ string) string {
+	return e.clist.down()
+}
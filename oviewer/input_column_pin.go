@@ -0,0 +1,61 @@
+package oviewer
+
+import (
+	"strconv"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// setColumnPinMode sets the inputMode to ColumnPin.
+func (root *Root) setColumnPinMode() {
+	input := root.input
+	input.value = ""
+	input.cursorX = 0
+	input.Event = newColumnPinEvent()
+}
+
+// eventColumnPin represents the pinned columns input mode.
+type eventColumnPin struct {
+	tcell.EventTime
+	value string
+}
+
+// newColumnPinEvent returns columnPinEvent.
+func newColumnPinEvent() *eventColumnPin {
+	return &eventColumnPin{}
+}
+
+// Mode returns InputMode.
+func (*eventColumnPin) Mode() InputMode {
+	return ColumnPin
+}
+
+// Prompt returns the prompt string in the input field.
+func (*eventColumnPin) Prompt() string {
+	return "Pin columns:"
+}
+
+// Confirm returns the event when the input is confirmed.
+func (e *eventColumnPin) Confirm(str string) tcell.Event {
+	e.value = str
+	e.SetEventNow()
+	return e
+}
+
+// Up returns strings when the up key is pressed during input.
+func (*eventColumnPin) Up(str string) string {
+	n, err := strconv.Atoi(str)
+	if err != nil {
+		return "0"
+	}
+	return strconv.Itoa(n + 1)
+}
+
+// Down returns strings when the down key is pressed during input.
+func (*eventColumnPin) Down(str string) string {
+	n, err := strconv.Atoi(str)
+	if err != nil || n <= 0 {
+		return "0"
+	}
+	return strconv.Itoa(n - 1)
+}
@@ -0,0 +1,68 @@
+package oviewer
+
+import (
+	"github.com/gdamore/tcell/v2"
+)
+
+// eventInputJSONFilter represents the JSON filter input mode.
+type eventInputJSONFilter struct {
+	tcell.EventTime
+	clist *candidate
+	value string
+}
+
+// setJSONFilterMode sets the inputMode to JSONFilter.
+func (root *Root) setJSONFilterMode() {
+	input := root.input
+	input.value = ""
+	input.cursorX = 0
+
+	root.Doc.nonMatch = false
+	input.Event = newJSONFilterEvent(input.JSONFilterCandidate)
+}
+
+// jsonFilterCandidate returns the candidate to set to default.
+func jsonFilterCandidate() *candidate {
+	return &candidate{
+		list: []string{},
+	}
+}
+
+// newJSONFilterEvent returns JSONFilterInput.
+func newJSONFilterEvent(clist *candidate) *eventInputJSONFilter {
+	return &eventInputJSONFilter{
+		value:     "",
+		clist:     clist,
+		EventTime: tcell.EventTime{},
+	}
+}
+
+// Mode returns InputMode.
+func (*eventInputJSONFilter) Mode() InputMode {
+	return JSONFilter
+}
+
+// Prompt returns the prompt string in the input field.
+func (*eventInputJSONFilter) Prompt() string {
+	return "JSON filter:"
+}
+
+// Confirm returns the event when the input is confirmed.
+func (e *eventInputJSONFilter) Confirm(str string) tcell.Event {
+	e.value = str
+	e.clist.toLast(str)
+	e.SetEventNow()
+	return e
+}
+
+// Up returns strings when the up key is pressed during input.
+func (e *eventInputJSONFilter) Up(str string) string {
+	e.clist.toAddLast(str)
+	return e.clist.up()
+}
+
+// Down returns strings when the down key is pressed during input.
+func (e *eventInputJSONFilter) Down(str string) string {
+	e.clist.toAddTop(str)
+	return e.clist.down()
+}
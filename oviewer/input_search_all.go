@@ -0,0 +1,65 @@
+package oviewer
+
+import (
+	"github.com/gdamore/tcell/v2"
+)
+
+// eventInputSearchAll represents the search all input mode.
+type eventInputSearchAll struct {
+	tcell.EventTime
+	clist *candidate
+	value string
+}
+
+// setSearchAllMode sets the inputMode to SearchAll.
+func (root *Root) setSearchAllMode() {
+	input := root.input
+	input.value = ""
+	input.cursorX = 0
+
+	root.Doc.nonMatch = false
+	if root.searcher != nil {
+		input.SearchCandidate.toLast(root.searcher.String())
+	}
+
+	input.Event = newSearchAllEvent(input.SearchCandidate)
+}
+
+// newSearchAllEvent returns SearchAllInput.
+func newSearchAllEvent(clist *candidate) *eventInputSearchAll {
+	return &eventInputSearchAll{
+		value:     "",
+		clist:     clist,
+		EventTime: tcell.EventTime{},
+	}
+}
+
+// Mode returns InputMode.
+func (*eventInputSearchAll) Mode() InputMode {
+	return SearchAll
+}
+
+// Prompt returns the prompt string in the input field.
+func (*eventInputSearchAll) Prompt() string {
+	return "Search all:"
+}
+
+// Confirm returns the event when the input is confirmed.
+func (e *eventInputSearchAll) Confirm(str string) tcell.Event {
+	e.value = str
+	e.clist.toLast(str)
+	e.SetEventNow()
+	return e
+}
+
+// Up returns strings when the up key is pressed during input.
+func (e *eventInputSearchAll) Up(str string) string {
+	e.clist.toAddLast(str)
+	return e.clist.up()
+}
+
+// Down returns strings when the down key is pressed during input.
+func (e *eventInputSearchAll) Down(str string) string {
+	e.clist.toAddTop(str)
+	return e.clist.down()
+}
@@ -0,0 +1,75 @@
+package oviewer
+
+import (
+	"github.com/gdamore/tcell/v2"
+)
+
+// eventInputSort represents the sort input mode.
+type eventInputSort struct {
+	tcell.EventTime
+	clist *candidate
+	value string
+}
+
+// setSortMode sets the inputMode to Sort.
+func (root *Root) setSortMode() {
+	input := root.input
+	input.value = ""
+	input.cursorX = 0
+
+	input.Event = newSortEvent(input.SortCandidate)
+}
+
+// sortCandidate returns the candidate to set to default.
+// The "-" prefix sorts in descending order.
+func sortCandidate() *candidate {
+	return &candidate{
+		list: []string{
+			"lexical",
+			"numeric",
+			"size",
+			"time",
+			"-lexical",
+			"-numeric",
+			"-size",
+			"-time",
+		},
+	}
+}
+
+// newSortEvent returns SortInput.
+func newSortEvent(clist *candidate) *eventInputSort {
+	return &eventInputSort{
+		value:     "",
+		clist:     clist,
+		EventTime: tcell.EventTime{},
+	}
+}
+
+// Mode returns InputMode.
+func (*eventInputSort) Mode() InputMode {
+	return Sort
+}
+
+// Prompt returns the prompt string in the input field.
+func (*eventInputSort) Prompt() string {
+	return "Sort:"
+}
+
+// Confirm returns the event when the input is confirmed.
+func (e *eventInputSort) Confirm(str string) tcell.Event {
+	e.value = str
+	e.clist.toLast(str)
+	e.SetEventNow()
+	return e
+}
+
+// Up returns strings when the up key is pressed during input.
+func (e *eventInputSort) Up(_ string) string {
+	return e.clist.up()
+}
+
+// Down returns strings when the down key is pressed during input.
+func (e *eventInputSort) Down(_ string) string {
+	return e.clist.down()
+}
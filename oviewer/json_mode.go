@@ -0,0 +1,134 @@
+package oviewer
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// jsonIndent is the number of spaces of one level of indentation in JSON mode.
+const jsonIndent = 2
+
+// jsonBreakContent is a line break in the contents of JSON mode.
+// It has no width and the following contents are drawn from the next screen line.
+var jsonBreakContent = content{
+	mainc: '\n',
+	combc: nil,
+	width: 0,
+	style: tcell.StyleDefault,
+}
+
+// jsonContents returns the contents of the line pretty-printed as indented JSON.
+// The text before the object such as a timestamp is displayed as it is.
+// It returns false if the line is not a JSON object or array.
+func jsonContents(str string, tabWidth int) (contents, bool) {
+	s := stripEscapeSequenceString(str)
+	i := strings.IndexByte(s, '{')
+	if t := strings.TrimLeft(s, " \t"); strings.HasPrefix(t, "[") {
+		i = len(s) - len(t)
+	}
+	if i < 0 || !json.Valid([]byte(s[i:])) {
+		return nil, false
+	}
+
+	p := jsonPrinter{lc: parseString(s[:i], tabWidth)}
+	p.print(s[i:])
+	return p.lc, true
+}
+
+// jsonPrinter converts valid JSON to the indented contents.
+type jsonPrinter struct {
+	lc    contents
+	stack []byte
+}
+
+// print appends the indented contents of the valid JSON.
+// The order of the keys and the notation of the values are kept.
+func (p *jsonPrinter) print(b string) {
+	expectKey := false
+	for i := 0; i < len(b); {
+		c := b[i]
+		switch c {
+		case ' ', '\t', '\r', '\n':
+			i++
+		case '{', '[':
+			p.add(b[i:i+1], tcell.StyleDefault)
+			i = skipJSONSpace(b, i+1)
+			if i < len(b) && (b[i] == '}' || b[i] == ']') {
+				// Empty object or array.
+				p.add(b[i:i+1], tcell.StyleDefault)
+				i++
+				continue
+			}
+			p.stack = append(p.stack, c)
+			p.lineBreak()
+			expectKey = c == '{'
+		case '}', ']':
+			p.stack = p.stack[:len(p.stack)-1]
+			p.lineBreak()
+			p.add(b[i:i+1], tcell.StyleDefault)
+			i++
+		case ',':
+			p.add(",", tcell.StyleDefault)
+			p.lineBreak()
+			expectKey = len(p.stack) > 0 && p.stack[len(p.stack)-1] == '{'
+			i++
+		case ':':
+			p.add(": ", tcell.StyleDefault)
+			i++
+		case '"':
+			end := jsonStringEnd(b, i)
+			style := JSONStringStyle
+			if expectKey {
+				style = JSONKeyStyle
+				expectKey = false
+			}
+			p.add(b[i:end], style)
+			i = end
+		default:
+			end := i
+			for end < len(b) && !strings.ContainsRune(" \t\r\n,:]}", rune(b[end])) {
+				end++
+			}
+			p.add(b[i:end], JSONValueStyle)
+			i = end
+		}
+	}
+}
+
+// add appends the string with the style.
+func (p *jsonPrinter) add(s string, style tcell.Style) {
+	lc := parseString(s, -1)
+	for n := range lc {
+		lc[n].style = style
+	}
+	p.lc = append(p.lc, lc...)
+}
+
+// lineBreak appends a line break and the indentation of the current depth.
+func (p *jsonPrinter) lineBreak() {
+	p.lc = append(p.lc, jsonBreakContent)
+	p.add(strings.Repeat(" ", len(p.stack)*jsonIndent), tcell.StyleDefault)
+}
+
+// skipJSONSpace returns the position of the first non-space character from i.
+func skipJSONSpace(b string, i int) int {
+	for i < len(b) && strings.IndexByte(" \t\r\n", b[i]) >= 0 {
+		i++
+	}
+	return i
+}
+
+// jsonStringEnd returns the position after the closing quote of the string starting at i.
+func jsonStringEnd(b string, i int) int {
+	for j := i + 1; j < len(b); j++ {
+		switch b[j] {
+		case '\\':
+			j++
+		case '"':
+			return j + 1
+		}
+	}
+	return len(b)
+}
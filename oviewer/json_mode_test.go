@@ -0,0 +1,150 @@
+package oviewer
+
+import (
+	"strings"
+	"testing"
+)
+
+// jsonTestContents returns the contents of JSON mode.
+func jsonTestContents(t *testing.T, str string) contents {
+	t.Helper()
+	lc, ok := jsonContents(str, 8)
+	if !ok {
+		t.Fatalf("jsonContents(%q) is not JSON", str)
+	}
+	return lc
+}
+
+func Test_jsonContents(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		str    string
+		want   string
+		wantOK bool
+	}{
+		{
+			name: "testObject",
+			str:  `{"name":"ov","tags":["pager", "go"],"v":1.0,"ok":true,"n":null}`,
+			want: `{
+  "name": "ov",
+  "tags": [
+    "pager",
+    "go"
+  ],
+  "v": 1.0,
+  "ok": true,
+  "n": null
+}`,
+			wantOK: true,
+		},
+		{
+			name: "testNested",
+			str:  `{"a":{"b":{},"c":[]},"d":"x,y:{z}"}`,
+			want: `{
+  "a": {
+    "b": {},
+    "c": []
+  },
+  "d": "x,y:{z}"
+}`,
+			wantOK: true,
+		},
+		{
+			name: "testArray",
+			str:  `  [1, {"a":"\"}"}]`,
+			want: `  [
+  1,
+  {
+    "a": "\"}"
+  }
+]`,
+			wantOK: true,
+		},
+		{
+			name: "testPrefix",
+			str:  `2024-01-02 {"level":"info"}`,
+			want: `2024-01-02 {
+  "level": "info"
+}`,
+			wantOK: true,
+		},
+		{
+			name: "testEscapeSequence",
+			str:  "\x1b[32m{\"a\":1}\x1b[m",
+			want: `{
+  "a": 1
+}`,
+			wantOK: true,
+		},
+		{
+			name:   "testNotJSON",
+			str:    `{"a":1`,
+			wantOK: false,
+		},
+		{
+			name:   "testNoObject",
+			str:    `plain text`,
+			wantOK: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			lc, ok := jsonContents(tt.str, 8)
+			if ok != tt.wantOK {
+				t.Fatalf("jsonContents() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if got, _ := ContentsToStr(lc); got != tt.want {
+				t.Errorf("jsonContents() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocument_contentsJSONMode(t *testing.T) {
+	t.Parallel()
+	m, err := NewDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := strings.NewReader("{\"header\":1}\n{\"a\":1}\nplain\n")
+	if err := m.ControlReader(r, nil); err != nil {
+		t.Fatal(err)
+	}
+	for !m.BufEOF() {
+	}
+	m.Header = 1
+	m.WrapMode = true
+	m.JSONMode = true
+	tests := []struct {
+		lN   int
+		want string
+	}{
+		{lN: 0, want: `{"header":1}`},
+		{lN: 1, want: "{\n  \"a\": 1\n}"},
+		{lN: 2, want: "plain"},
+	}
+	for _, tt := range tests {
+		lc, err := m.contents(tt.lN, 8)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := ContentsToStr(lc); got != tt.want {
+			t.Errorf("contents(%d) = %q, want %q", tt.lN, got, tt.want)
+		}
+	}
+
+	m.WrapMode = false
+	lc, err := m.contents(1, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := ContentsToStr(lc); got != `{"a":1}` {
+		t.Errorf("contents(1) in nowrap mode = %q", got)
+	}
+}
@@ -22,6 +22,7 @@ const (
 	actionFollowAll      = "follow_all"
 	actionFollowSection  = "follow_section"
 	actionPlain          = "plain_mode"
+	actionJSONMode       = "json_mode"
 	actionRainbow        = "rainbow_mode"
 	actionCloseFile      = "close_file"
 	actionReload         = "reload"
@@ -60,9 +61,21 @@ const (
 	actionLineNumMode    = "line_number_mode"
 	actionSearch         = "search"
 	actionFilter         = "filter"
+	actionSearchAll      = "search_all"
+	actionJSONFilter     = "json_filter"
+	actionSort           = "sort"
+	actionJumpToParent   = "jump_to_parent"
+	actionColumnStats    = "column_stats"
 	actionWrap           = "wrap_mode"
 	actionColumnMode     = "column_mode"
 	actionColumnWidth    = "column_width"
+	actionColumnLogfmt   = "column_logfmt"
+	actionColumnKey      = "column_key"
+	actionColumnCSV      = "column_csv"
+	actionColumnAlign    = "column_align"
+	actionColumnOrder    = "column_order"
+	actionColumnPin      = "column_pin"
+	actionHideColumn     = "hide_column"
 	actionBackSearch     = "backsearch"
 	actionDelimiter      = "delimiter"
 	actionHeader         = "header"
@@ -85,6 +98,9 @@ const (
 	inputSmartCaseSensitive = "input_smart_casesensitive"
 	inputIncSearch          = "input_incsearch"
 	inputRegexpSearch       = "input_regexp_search"
+	inputBooleanSearch      = "input_boolean_search"
+	inputFuzzySearch        = "input_fuzzy_search"
+	inputColumnSearch       = "input_column_search"
 	inputNonMatch           = "input_non_match"
 	inputPrevious           = "input_previous"
 	inputNext               = "input_next"
@@ -105,6 +121,7 @@ func (root *Root) handlers() map[string]func() {
 		actionFollowAll:      root.toggleFollowAll,
 		actionFollowSection:  root.toggleFollowSection,
 		actionPlain:          root.togglePlain,
+		actionJSONMode:       root.toggleJSONMode,
 		actionRainbow:        root.toggleRainbow,
 		actionReload:         root.Reload,
 		actionWatch:          root.toggleWatch,
@@ -139,6 +156,13 @@ func (root *Root) handlers() map[string]func() {
 		actionWrap:           root.toggleWrapMode,
 		actionColumnMode:     root.toggleColumnMode,
 		actionColumnWidth:    root.toggleColumnWidth,
+		actionColumnLogfmt:   root.toggleColumnLogfmt,
+		actionColumnKey:      root.setColumnKeyMode,
+		actionColumnCSV:      root.toggleColumnCSV,
+		actionColumnAlign:    root.toggleColumnAlign,
+		actionColumnOrder:    root.setColumnOrderMode,
+		actionColumnPin:      root.setColumnPinMode,
+		actionHideColumn:     root.hideColumn,
 		actionAlternate:      root.toggleAlternateRows,
 		actionLineNumMode:    root.toggleLineNumMode,
 		actionMark:           root.addMark,
@@ -147,6 +171,11 @@ func (root *Root) handlers() map[string]func() {
 		actionSearch:         root.setSearchMode,
 		actionBackSearch:     root.setBackSearchMode,
 		actionFilter:         root.setSearchFilterMode,
+		actionSearchAll:      root.setSearchAllMode,
+		actionJSONFilter:     root.setJSONFilterMode,
+		actionSort:           root.setSortMode,
+		actionJumpToParent:   root.parentJump,
+		actionColumnStats:    root.sendColumnStats,
 		actionDelimiter:      root.setDelimiterMode,
 		actionHeader:         root.setHeaderMode,
 		actionSkipLines:      root.setSkipLinesMode,
@@ -168,6 +197,9 @@ func (root *Root) handlers() map[string]func() {
 		inputSmartCaseSensitive: root.inputSmartCaseSensitive,
 		inputIncSearch:          root.inputIncSearch,
 		inputRegexpSearch:       root.inputRegexpSearch,
+		inputBooleanSearch:      root.inputBooleanSearch,
+		inputFuzzySearch:        root.inputFuzzySearch,
+		inputColumnSearch:       root.inputColumnSearch,
 		inputNonMatch:           root.inputNonMatch,
 		inputPrevious:           root.inputPrevious,
 		inputNext:               root.inputNext,
@@ -191,6 +223,7 @@ func defaultKeyBinds() KeyBind {
 		actionFollowAll:      {"ctrl+a"},
 		actionFollowSection:  {"F2"},
 		actionPlain:          {"ctrl+e"},
+		actionJSONMode:       {"I"},
 		actionRainbow:        {"ctrl+r"},
 		actionCloseFile:      {"ctrl+F9", "ctrl+alt+s"},
 		actionReload:         {"F5", "ctrl+alt+l"},
@@ -225,6 +258,13 @@ func defaultKeyBinds() KeyBind {
 		actionWrap:           {"w", "W"},
 		actionColumnMode:     {"c"},
 		actionColumnWidth:    {"alt+o"},
+		actionColumnLogfmt:   {"alt+k"},
+		actionColumnKey:      {"alt+y"},
+		actionColumnCSV:      {"alt+q"},
+		actionColumnAlign:    {"alt+a"},
+		actionColumnOrder:    {"alt+e"},
+		actionColumnPin:      {"alt+p"},
+		actionHideColumn:     {"alt+h"},
 		actionAlternate:      {"C"},
 		actionLineNumMode:    {"G"},
 		actionMark:           {"m"},
@@ -233,6 +273,11 @@ func defaultKeyBinds() KeyBind {
 		actionSearch:         {"/"},
 		actionBackSearch:     {"?"},
 		actionFilter:         {"&"},
+		actionSearchAll:      {"alt+/"},
+		actionJSONFilter:     {"alt+u"},
+		actionSort:           {"alt+t"},
+		actionJumpToParent:   {"o"},
+		actionColumnStats:    {"alt+g"},
 		actionDelimiter:      {"d"},
 		actionHeader:         {"H"},
 		actionSkipLines:      {"ctrl+s"},
@@ -255,6 +300,9 @@ func defaultKeyBinds() KeyBind {
 		inputSmartCaseSensitive: {"alt+s"},
 		inputIncSearch:          {"alt+i"},
 		inputRegexpSearch:       {"alt+r"},
+		inputBooleanSearch:      {"alt+b"},
+		inputFuzzySearch:        {"alt+f"},
+		inputColumnSearch:       {"alt+l"},
 		inputNonMatch:           {"!"},
 		inputPrevious:           {"Up"},
 		inputNext:               {"Down"},
@@ -318,19 +366,32 @@ func (k KeyBind) String() string {
 	k.writeKeyBind(&b, actionNextSearch, "repeat forward search")
 	k.writeKeyBind(&b, actionNextBackSearch, "repeat backward search")
 	k.writeKeyBind(&b, actionFilter, "filter search mode")
+	k.writeKeyBind(&b, actionSearchAll, "search all mode")
+	k.writeKeyBind(&b, actionJSONFilter, "JSON filter mode")
+	k.writeKeyBind(&b, actionSort, "sort by column mode")
+	k.writeKeyBind(&b, actionJumpToParent, "jump to the line in the original document")
+	k.writeKeyBind(&b, actionColumnStats, "statistics of the column")
 
 	writeHeader(&b, "Change display")
 	k.writeKeyBind(&b, actionWrap, "wrap/nowrap toggle")
 	k.writeKeyBind(&b, actionColumnMode, "column mode toggle")
 	k.writeKeyBind(&b, actionColumnWidth, "column width toggle")
+	k.writeKeyBind(&b, actionColumnLogfmt, "column logfmt toggle")
+	k.writeKeyBind(&b, actionColumnCSV, "column CSV quote toggle")
+	k.writeKeyBind(&b, actionColumnAlign, "column align toggle")
+	k.writeKeyBind(&b, actionHideColumn, "hide the column of the cursor")
 	k.writeKeyBind(&b, actionRainbow, "column rainbow toggle")
 	k.writeKeyBind(&b, actionAlternate, "alternate rows of style toggle")
 	k.writeKeyBind(&b, actionLineNumMode, "line number toggle")
 	k.writeKeyBind(&b, actionPlain, "original decoration toggle(plain)")
+	k.writeKeyBind(&b, actionJSONMode, "JSON pretty-print toggle")
 
 	writeHeader(&b, "Change Display with Input")
 	k.writeKeyBind(&b, actionViewMode, "view mode selection")
 	k.writeKeyBind(&b, actionDelimiter, "column delimiter string")
+	k.writeKeyBind(&b, actionColumnKey, "column key of logfmt")
+	k.writeKeyBind(&b, actionColumnOrder, "column order(empty to show all)")
+	k.writeKeyBind(&b, actionColumnPin, "number of pinned columns")
 	k.writeKeyBind(&b, actionHeader, "number of header lines")
 	k.writeKeyBind(&b, actionSkipLines, "number of skip lines")
 	k.writeKeyBind(&b, actionTabWidth, "TAB width")
@@ -356,6 +417,9 @@ func (k KeyBind) String() string {
 	k.writeKeyBind(&b, inputCaseSensitive, "case-sensitive toggle")
 	k.writeKeyBind(&b, inputSmartCaseSensitive, "smart case-sensitive toggle")
 	k.writeKeyBind(&b, inputRegexpSearch, "regular expression search toggle")
+	k.writeKeyBind(&b, inputBooleanSearch, "boolean search toggle")
+	k.writeKeyBind(&b, inputFuzzySearch, "fuzzy search toggle")
+	k.writeKeyBind(&b, inputColumnSearch, "column search toggle")
 	k.writeKeyBind(&b, inputIncSearch, "incremental search toggle")
 	k.writeKeyBind(&b, inputNonMatch, "non-match toggle")
 	k.writeKeyBind(&b, inputPrevious, "previous candidate")
@@ -0,0 +1,19 @@
+//go:build !windows
+// +build !windows
+
+package oviewer
+
+import (
+	"os"
+	"syscall"
+)
+
+// mmapFile maps the file into memory as read-only.
+func mmapFile(f *os.File, size int64) ([]byte, error) {
+	return syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+}
+
+// munmapFile unmaps the mapped file.
+func munmapFile(b []byte) error {
+	return syscall.Munmap(b)
+}
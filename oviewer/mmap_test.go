@@ -0,0 +1,203 @@
+//go:build !windows
+// +build !windows
+
+package oviewer
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"unsafe"
+)
+
+func TestDocument_loadChunkMmap(t *testing.T) {
+	MmapFile = true
+	defer func() {
+		MmapFile = false
+	}()
+
+	tests := []struct {
+		name string
+		str  string
+	}{
+		{
+			name: "testNewlineEOF",
+			str:  numberLines(35000),
+		},
+		{
+			name: "testNoNewlineEOF",
+			str:  numberLines(35000) + "end",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileName := filepath.Join(t.TempDir(), "mmap.txt")
+			if err := os.WriteFile(fileName, []byte(tt.str), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			m := openEOF(t, fileName)
+			if m.mmap == nil {
+				t.Fatal("not mapped")
+			}
+			lines := bytes.SplitAfter([]byte(tt.str), []byte("\n"))
+			if len(lines[len(lines)-1]) == 0 {
+				lines = lines[:len(lines)-1]
+			}
+			if m.BufEndNum() != len(lines) {
+				t.Fatalf("BufEndNum() = %d, want %d", m.BufEndNum(), len(lines))
+			}
+			for chunkNum := 1; chunkNum <= m.store.lastChunkNum(); chunkNum++ {
+				if !m.loadChunkMmap(chunkNum) {
+					t.Fatalf("loadChunkMmap(%d) = false", chunkNum)
+				}
+				start, end := m.store.chunkRange(chunkNum)
+				if got := len(m.store.chunks[chunkNum].lines); got != end-start {
+					t.Errorf("chunk %d lines = %d, want %d", chunkNum, got, end-start)
+				}
+				for _, cn := range []int{0, end - 1} {
+					n := chunkNum*ChunkSize + cn
+					got, err := m.store.GetChunkLine(chunkNum, cn)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if want := bytes.TrimSuffix(lines[n], []byte("\n")); !bytes.Equal(got, want) {
+						t.Errorf("line %d = %q, want %q", n, got, want)
+					}
+					// The line refers to the mapping.
+					p := uintptr(unsafe.Pointer(&m.store.chunks[chunkNum].lines[cn][0]))
+					base := uintptr(unsafe.Pointer(&m.mmap[0]))
+					if p < base || p >= base+uintptr(len(m.mmap)) {
+						t.Errorf("line %d is not in the mapping", n)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestDocument_closeMmap(t *testing.T) {
+	MmapFile = true
+	defer func() {
+		MmapFile = false
+	}()
+
+	fileName := filepath.Join(t.TempDir(), "mmap.txt")
+	if err := os.WriteFile(fileName, []byte(numberLines(25000)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m := openEOF(t, fileName)
+	if !m.loadChunkMmap(1) {
+		t.Fatal("loadChunkMmap(1) = false")
+	}
+	if !m.requestClose() {
+		t.Fatal("requestClose() = false")
+	}
+	if m.mmaps != nil {
+		t.Error("mappings are not unmapped")
+	}
+	for chunkNum, chunk := range m.store.chunks {
+		if chunk.mapped || (chunkNum == 1 && len(chunk.lines) != 0) {
+			t.Errorf("chunk %d refers to the unmapped file", chunkNum)
+		}
+	}
+}
+
+func TestDocument_checkTruncated(t *testing.T) {
+	MmapFile = true
+	defer func() {
+		MmapFile = false
+	}()
+
+	fileName := filepath.Join(t.TempDir(), "mmap.txt")
+	if err := os.WriteFile(fileName, []byte(numberLines(25000)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m := openEOF(t, fileName)
+	if !m.loadChunkMmap(1) {
+		t.Fatal("loadChunkMmap(1) = false")
+	}
+	if m.checkTruncated() {
+		t.Fatal("checkTruncated() = true before truncate")
+	}
+	if err := os.Truncate(fileName, 0); err != nil {
+		t.Fatal(err)
+	}
+	if !m.checkTruncated() {
+		t.Fatal("checkTruncated() = false after truncate")
+	}
+	if m.mmap != nil || m.mmaps != nil {
+		t.Error("mapping is not released")
+	}
+	if m.store.chunks[1].lines != nil {
+		t.Error("mapped lines are not unloaded")
+	}
+	if m.loadChunkMmap(1) {
+		t.Error("loadChunkMmap(1) = true after truncate")
+	}
+}
+
+func TestStore_GetChunkLineTruncated(t *testing.T) {
+	MmapFile = true
+	defer func() {
+		MmapFile = false
+	}()
+
+	fileName := filepath.Join(t.TempDir(), "mmap.txt")
+	if err := os.WriteFile(fileName, []byte(numberLines(25000)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m := openEOF(t, fileName)
+	if !m.loadChunkMmap(1) {
+		t.Fatal("loadChunkMmap(1) = false")
+	}
+	// The file is truncated while the mapped lines are in memory.
+	if err := os.Truncate(fileName, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.store.GetChunkLine(1, 0); !errors.Is(err, ErrTruncated) {
+		t.Fatalf("GetChunkLine() error = %v, want %v", err, ErrTruncated)
+	}
+	m.currentChunk = 1
+	if _, err := m.Line(ChunkSize); !errors.Is(err, ErrTruncated) {
+		t.Fatalf("Line() error = %v, want %v", err, ErrTruncated)
+	}
+	if m.mmap != nil || m.store.chunks[1].mapped {
+		t.Error("mapping is not released")
+	}
+}
+
+func TestDocument_remap(t *testing.T) {
+	MmapFile = true
+	defer func() {
+		MmapFile = false
+	}()
+
+	fileName := filepath.Join(t.TempDir(), "mmap.txt")
+	if err := os.WriteFile(fileName, []byte(numberLines(100)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m := openEOF(t, fileName)
+	size := len(m.mmap)
+	f, err := os.OpenFile(fileName, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString("added\n"); err != nil {
+		t.Fatal(err)
+	}
+	if !m.remap() {
+		t.Fatal("remap() = false after growth")
+	}
+	if len(m.mmap) != size*2 {
+		t.Errorf("mapping size = %d, want %d", len(m.mmap), size*2)
+	}
+	if _, err := f.WriteString("added\n"); err != nil {
+		t.Fatal(err)
+	}
+	if m.remap() {
+		t.Error("remap() = true within the mapping")
+	}
+}
@@ -0,0 +1,18 @@
+//go:build windows
+// +build windows
+
+package oviewer
+
+import (
+	"os"
+)
+
+// Dummy function because mmap is not supported in windows.
+func mmapFile(_ *os.File, _ int64) ([]byte, error) {
+	return nil, ErrNotSupported
+}
+
+// Dummy function because mmap is not supported in windows.
+func munmapFile(_ []byte) error {
+	return nil
+}
@@ -29,7 +29,14 @@ func (m *Document) optimalCursor(cursor int) int {
 	if m.WrapMode {
 		return cursor
 	}
+	// The pinned columns are always displayed.
+	if cursor < m.ColumnPin {
+		return cursor
+	}
 
+	if m.ColumnLogfmt {
+		return min(cursor, m.rightmostColumn())
+	}
 	if m.ColumnWidth {
 		return m.optimalCursorWidth(cursor)
 	}
@@ -42,7 +49,7 @@ func (m *Document) optimalCursorWidth(cursor int) int {
 	if !valid {
 		return cursor
 	}
-	return optimalCursor(line, m.columnWidths, cursor, m.x, m.x+m.width)
+	return optimalCursor(line, m.widthPositions(), cursor, m.x, m.x+m.width)
 }
 
 // optimalCursorDelimiter returns the optimal cursor position when in columnDelimiter mode.
@@ -52,7 +59,7 @@ func (m *Document) optimalCursorDelimiter(cursor int) int {
 		if !valid {
 			continue
 		}
-		widths := splitByDelimiter(line.str, m.ColumnDelimiter, m.ColumnDelimiterReg)
+		widths := m.splitColumns(line.str)
 		if len(widths) <= cursor {
 			continue
 		}
@@ -102,6 +109,9 @@ func (m *Document) optimalX(cursor int) (int, error) {
 		return 0, nil
 	}
 
+	if m.ColumnLogfmt {
+		return m.optimalXLogfmt(cursor)
+	}
 	if m.ColumnWidth {
 		return m.optimalXWidth(cursor)
 	}
@@ -110,10 +120,23 @@ func (m *Document) optimalX(cursor int) (int, error) {
 
 // optimalXWidth returns the optimal x position of the column at the specified cursor position.
 func (m *Document) optimalXWidth(cursor int) (int, error) {
-	if cursor < len(m.columnWidths) {
-		return m.columnWidths[cursor-1], nil
+	positions := m.widthPositions()
+	if len(positions) == 0 {
+		return 0, ErrNoColumn
+	}
+	if cursor < len(positions) {
+		return positions[cursor-1], nil
+	}
+	return positions[len(positions)-1], nil
+}
+
+// optimalXLogfmt returns the x position of the logfmt column at the specified cursor position.
+func (m *Document) optimalXLogfmt(cursor int) (int, error) {
+	positions := m.logfmtPositions()
+	if len(positions) == 0 {
+		return 0, ErrNoColumn
 	}
-	return m.columnWidths[len(m.columnWidths)-1], nil
+	return positions[min(cursor, len(positions))-1], nil
 }
 
 // optimalXDelimiter returns the best x position of the column at the specified cursor position.
@@ -123,7 +146,7 @@ func (m *Document) optimalXDelimiter(cursor int) (int, error) {
 		if !valid {
 			continue
 		}
-		widths := splitByDelimiter(line.str, m.ColumnDelimiter, m.ColumnDelimiterReg)
+		widths := m.splitColumns(line.str)
 		if cursor > 0 && cursor < len(widths) {
 			return line.pos.x(widths[cursor]) - columnMargin, nil
 		}
@@ -135,22 +158,32 @@ func (m *Document) optimalXDelimiter(cursor int) (int, error) {
 // If the cursor is out of range, it returns an error.
 // moveTo is positive for right(+1) and negative for left(-1).
 func (m *Document) moveTo(scr SCR, moveTo int) (int, int, error) {
+	if cursor := m.columnCursor + moveTo; !m.WrapMode && cursor >= 0 && cursor < m.ColumnPin {
+		// The pinned columns are always displayed.
+		return m.x, cursor, nil
+	}
+	pin := m.pinnedScreenWidth()
+	if m.ColumnLogfmt {
+		return m.moveToPositions(scr, moveTo, m.logfmtPositions(), pin)
+	}
 	if m.ColumnWidth {
-		return m.moveToWidth(scr, moveTo)
+		return m.moveToPositions(scr, moveTo, m.widthPositions(), pin)
 	}
-	return m.moveToDelimiter(moveTo)
+	return m.moveToDelimiter(moveTo, pin)
 }
 
-// moveToWidth returns x and cursor from the orientation to move.
-func (m *Document) moveToWidth(scr SCR, moveTo int) (int, int, error) {
+// moveToPositions returns x and cursor from the orientation to move.
+// positions are the start positions of the columns after the first column.
+// pin is the width of the pinned columns that hide the left side of the screen.
+func (m *Document) moveToPositions(scr SCR, moveTo int, positions []int, pin int) (int, int, error) {
 	cursor := m.columnCursor + moveTo
 	if cursor < 0 {
 		return m.x, m.columnCursor, ErrNoColumn
 	}
 
-	widths := make([]int, 0, len(m.columnWidths)+2)
+	widths := make([]int, 0, len(positions)+2)
 	widths = append(widths, 0)
-	widths = append(widths, m.columnWidths...)
+	widths = append(widths, positions...)
 	var cl, cr int
 	if cursor < len(widths)-1 {
 		cl = widths[cursor]
@@ -159,11 +192,13 @@ func (m *Document) moveToWidth(scr SCR, moveTo int) (int, int, error) {
 		cl = widths[len(widths)-1]
 		cr = m.rightmost(scr)
 	}
-	return screenAdjustX(m.x, m.x+m.width, cl, cr, widths, cursor)
+	x, cursor, err := screenAdjustX(m.x+pin, m.x+m.width, cl, cr, widths, cursor)
+	return unpinnedX(x, pin), cursor, err
 }
 
 // moveToDelimiter returns x and cursor from the orientation to move.
-func (m *Document) moveToDelimiter(moveTo int) (int, int, error) {
+// pin is the width of the pinned columns that hide the left side of the screen.
+func (m *Document) moveToDelimiter(moveTo int, pin int) (int, int, error) {
 	width := m.width
 	if m.WrapMode {
 		// dummy width
@@ -177,7 +212,7 @@ func (m *Document) moveToDelimiter(moveTo int) (int, int, error) {
 		if !valid {
 			continue
 		}
-		widths := splitByDelimiter(line.str, m.ColumnDelimiter, m.ColumnDelimiterReg)
+		widths := m.splitColumns(line.str)
 		maxColumn = max(maxColumn, len(widths)-1)
 		if len(widths) <= 0 {
 			continue
@@ -188,11 +223,13 @@ func (m *Document) moveToDelimiter(moveTo int) (int, int, error) {
 			if cursor < len(widths)-1 {
 				cr = line.pos.x(widths[cursor+1])
 			}
-			return screenAdjustX(m.x, m.x+width, cl, cr, widths, cursor)
+			x, cursor, err := screenAdjustX(m.x+pin, m.x+width, cl, cr, widths, cursor)
+			return unpinnedX(x, pin), cursor, err
 		} else {
 			cl := line.pos.x(widths[len(widths)-1])
 			cr := line.pos.x(len(line.str))
-			return screenAdjustX(m.x, m.x+width, cl, cr, widths, cursor)
+			x, cursor, err := screenAdjustX(m.x+pin, m.x+width, cl, cr, widths, cursor)
+			return unpinnedX(x, pin), cursor, err
 		}
 	}
 
@@ -263,9 +300,21 @@ func screenAdjustX(left int, right int, cl int, cr int, widths []int, cursor int
 	return cl - columnMargin, cursor, nil
 }
 
+// unpinnedX returns x of the screen from the left edge of the columns that are not pinned.
+func unpinnedX(x int, pin int) int {
+	if pin == 0 {
+		return x
+	}
+	return max(0, x-pin)
+}
+
 // splitByDelimiter return a slice split by delimiter
 func splitByDelimiter(str string, delimiter string, delimiterReg *regexp.Regexp) []int {
-	indexes := allIndex(str, delimiter, delimiterReg)
+	return splitByIndex(str, allIndex(str, delimiter, delimiterReg))
+}
+
+// splitByIndex return a slice split by the indexes of the delimiters.
+func splitByIndex(str string, indexes [][]int) []int {
 	if len(indexes) == 0 {
 		return nil
 	}
@@ -370,8 +419,11 @@ func (m *Document) rightmost(scr SCR) int {
 
 // rightmostColumn returns the number of rightmost columns.
 func (m *Document) rightmostColumn() int {
+	if m.ColumnLogfmt {
+		return max(0, len(m.logfmtKeys)-1)
+	}
 	if m.ColumnWidth {
-		return len(m.columnWidths)
+		return len(m.widthPositions())
 	}
 
 	maxColumn := 0
@@ -380,7 +432,7 @@ func (m *Document) rightmostColumn() int {
 		if !valid {
 			continue
 		}
-		widths := splitByDelimiter(line.str, m.ColumnDelimiter, m.ColumnDelimiterReg)
+		widths := m.splitColumns(line.str)
 		maxColumn = max(maxColumn, len(widths)-1)
 	}
 	return maxColumn
@@ -233,21 +233,43 @@ func (m *Document) leftMostX(lN int) []int {
 }
 
 // leftX returns a list of left - most x positions when wrapping.
+// The line break in the contents of JSON mode starts a new position.
 func leftX(width int, lc contents) []int {
 	if width <= 0 {
 		return []int{0}
 	}
 	listX := make([]int, 0, (len(lc)/width)+1)
 	listX = append(listX, 0)
-	for n := width; n < len(lc); n += width {
-		if lc[n-1].width == 2 {
+	for x := 0; x < len(lc); {
+		n := x + width
+		if b := lineBreakX(lc, x, n); b >= 0 {
+			n = b + 1
+		} else if n >= len(lc) {
+			break
+		} else if lc[n-1].width == 2 && n-1 > x {
 			n--
 		}
+		if n >= len(lc) {
+			break
+		}
 		listX = append(listX, n)
+		x = n
 	}
 	return listX
 }
 
+// lineBreakX returns the position of the first line break from start to end (inclusive).
+// It returns -1 if there is no line break.
+func lineBreakX(lc contents, start int, end int) int {
+	end = min(end, len(lc)-1)
+	for x := start; x <= end; x++ {
+		if lc[x].mainc == '\n' {
+			return x
+		}
+	}
+	return -1
+}
+
 // moveNextSection moves to the next section.
 func (m *Document) moveNextSection() error {
 	// Move by page, if there is no section delimiter.
@@ -410,6 +410,22 @@ func Test_leftX(t *testing.T) {
 			},
 			want: []int{0, 79},
 		},
+		{
+			name: "jsonLineBreak",
+			args: args{
+				width: 80,
+				lc:    jsonTestContents(t, `{"a":1,"b":[]}`),
+			},
+			want: []int{0, 2, 12, 22},
+		},
+		{
+			name: "jsonLineBreakWrap",
+			args: args{
+				width: 5,
+				lc:    jsonTestContents(t, `{"a":1,"b":[]}`),
+			},
+			want: []int{0, 2, 7, 12, 17, 22},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -50,6 +50,8 @@ type Root struct {
 	message string
 	// cancelKeys represents the cancellation key string.
 	cancelKeys []string
+	// parentJumpKeys represents the key string to jump to the original document.
+	parentJumpKeys []string
 
 	// DocList is the list of documents.
 	DocList []*Document
@@ -149,6 +151,19 @@ type general struct {
 	ColumnMode bool
 	// ColumnWidth is column width mode.
 	ColumnWidth bool
+	// ColumnLogfmt is column mode for logfmt (key=value).
+	ColumnLogfmt bool
+	// ColumnCSV is column mode that respects the quoted fields of CSV (RFC 4180).
+	ColumnCSV bool
+	// ColumnAlign is column mode that aligns the columns separated by the delimiter.
+	ColumnAlign bool
+	// ColumnAuto detects the column delimiter and the header.
+	ColumnAuto bool
+	// ColumnOrder is the column numbers (1-based) to display in order.
+	// The columns that are not included are hidden.
+	ColumnOrder []int
+	// ColumnPin is the number of columns pinned to the left when scrolling horizontally.
+	ColumnPin int
 	// ColumnRainbow is column rainbow.
 	ColumnRainbow bool
 	// LineNumMode displays line numbers.
@@ -165,6 +180,8 @@ type general struct {
 	FollowName bool
 	// PlainMode is whether to enable the original character decoration.
 	PlainMode bool
+	// JSONMode is whether to display the JSON lines indented in wrap mode.
+	JSONMode bool
 	// SectionHeader is whether to display the section header.
 	SectionHeader bool
 }
@@ -227,6 +244,12 @@ type Config struct {
 	StyleOverStrike OVStyle
 	// StyleOverLine is a style that applies to overstrike underlines.
 	StyleOverLine OVStyle
+	// StyleJSONKey is a style that applies to the keys in JSON mode.
+	StyleJSONKey OVStyle
+	// StyleJSONString is a style that applies to the string values in JSON mode.
+	StyleJSONString OVStyle
+	// StyleJSONValue is a style that applies to the numbers, true, false and null in JSON mode.
+	StyleJSONValue OVStyle
 	// General represents the general behavior.
 	General general
 	// BeforeWriteOriginal specifies the number of lines before the current position.
@@ -253,6 +276,12 @@ type Config struct {
 	RegexpSearch bool
 	// Incsearch is incremental search if true.
 	Incsearch bool
+	// BooleanSearch is a search that combines terms with &(AND), |(OR) and !(NOT) if true.
+	BooleanSearch bool
+	// FuzzySearch is a search that matches the characters of the search word in order if true.
+	FuzzySearch bool
+	// ColumnSearch is a search only in the column of the cursor in column mode if true.
+	ColumnSearch bool
 
 	// DisableColumnCycle is disable column cycle.
 	DisableColumnCycle bool
@@ -312,8 +341,27 @@ var (
 	OverStrikeStyle tcell.Style
 	// OverLineStyle represents the overline underline style.
 	OverLineStyle tcell.Style
+	// JSONKeyStyle represents the style of the keys in JSON mode.
+	JSONKeyStyle tcell.Style
+	// JSONStringStyle represents the style of the string values in JSON mode.
+	JSONStringStyle tcell.Style
+	// JSONValueStyle represents the style of the other values in JSON mode.
+	JSONValueStyle tcell.Style
 	// SkipExtract is a flag to skip extracting compressed files.
 	SkipExtract bool
+	// IndexCache is a flag to cache the line index of seekable files on disk.
+	IndexCache bool
+	// IndexCacheDir is the directory of the index cache.
+	// If it is empty, the user cache directory is used.
+	IndexCacheDir string
+	// Spool is a flag to write the evicted chunks of non-seekable documents
+	// to a temporary file and read them again when needed.
+	// It is effective when MemoryLimit is specified.
+	Spool bool
+	// MmapFile is a flag to map regular files into memory instead of copying the lines.
+	// A truncated file is detected when a chunk is loaded and in follow mode,
+	// and then it is read without mapping.
+	MmapFile bool
 )
 
 // ov output destination.
@@ -378,6 +426,18 @@ var (
 	ErrAlreadyLoaded = errors.New("chunk already loaded")
 	// ErrEvictedMemory indicates that it has been evicted from memory.
 	ErrEvictedMemory = errors.New("evicted memory")
+	// ErrNotArchive indicates that the file is not an archive.
+	ErrNotArchive = errors.New("not an archive")
+	// ErrNotSupported indicates that it is not supported.
+	ErrNotSupported = errors.New("not supported")
+	// ErrInvalidJSONExpr indicates that the expression of the JSON filter is invalid.
+	ErrInvalidJSONExpr = errors.New("invalid JSON filter expression")
+	// ErrInvalidSortType indicates that the sort type is invalid.
+	ErrInvalidSortType = errors.New("invalid sort type")
+	// ErrSearchConflict indicates that fuzzy search and boolean search are enabled at the same time.
+	ErrSearchConflict = errors.New("fuzzy search and boolean search cannot be used together")
+	// ErrTruncated indicates that the mapped file has been truncated.
+	ErrTruncated = errors.New("truncated")
 )
 
 // This is a function of tcell.NewScreen but can be replaced with mock.
@@ -462,11 +522,11 @@ func openSTDIN() (*Root, error) {
 // openFile creates root in one file.
 // If there is only one file, an error will occur if the file fails to open.
 func openFile(fileName string) (*Root, error) {
-	m, err := OpenDocument(fileName)
+	docs, err := openDocuments(fileName)
 	if err != nil {
 		return nil, err
 	}
-	return NewOviewer(m)
+	return NewOviewer(docs...)
 }
 
 // openFiles opens multiple files and creates root.
@@ -475,12 +535,12 @@ func openFiles(fileNames []string) (*Root, error) {
 	errors := make([]string, 0)
 	docList := make([]*Document, 0)
 	for _, fileName := range fileNames {
-		m, err := OpenDocument(fileName)
+		docs, err := openDocuments(fileName)
 		if err != nil {
 			errors = append(errors, fmt.Sprintf("open error: %s", err))
 			continue
 		}
-		docList = append(docList, m)
+		docList = append(docList, docs...)
 	}
 
 	if len(docList) == 0 {
@@ -497,6 +557,23 @@ func openFiles(fileNames []string) (*Root, error) {
 	return root, err
 }
 
+// openDocuments opens the file and returns the documents.
+// An archive is opened as a document for each file in it.
+func openDocuments(fileName string) ([]*Document, error) {
+	docs, err := OpenArchive(fileName)
+	if err == nil {
+		return docs, nil
+	}
+	if !errors.Is(err, ErrNotArchive) {
+		return nil, err
+	}
+	m, err := OpenDocument(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return []*Document{m}, nil
+}
+
 // SetConfig sets config.
 func (root *Root) SetConfig(config Config) {
 	viewMode, overwrite := config.Mode[config.ViewMode]
@@ -581,6 +658,7 @@ func (root *Root) setKeyConfig() (map[string][]string, error) {
 	} else {
 		root.cancelKeys = keys
 	}
+	root.parentJumpKeys = keyBind[actionJumpToParent]
 	return keyBind, nil
 }
 
@@ -627,7 +705,7 @@ func (root *Root) Run() error {
 		if doc.FollowName {
 			doc.FollowMode = true
 		}
-		if doc.ColumnWidth {
+		if doc.ColumnWidth || doc.ColumnLogfmt || doc.ColumnCSV || doc.ColumnAlign || doc.ColumnAuto || len(doc.ColumnOrder) > 0 || doc.ColumnPin > 0 {
 			doc.ColumnMode = true
 		}
 		w := ""
@@ -848,6 +926,24 @@ func mergeGeneral(src general, dst general) general {
 	if dst.ColumnWidth {
 		src.ColumnWidth = dst.ColumnWidth
 	}
+	if dst.ColumnLogfmt {
+		src.ColumnLogfmt = dst.ColumnLogfmt
+	}
+	if dst.ColumnCSV {
+		src.ColumnCSV = dst.ColumnCSV
+	}
+	if dst.ColumnAlign {
+		src.ColumnAlign = dst.ColumnAlign
+	}
+	if dst.ColumnAuto {
+		src.ColumnAuto = dst.ColumnAuto
+	}
+	if len(dst.ColumnOrder) > 0 {
+		src.ColumnOrder = dst.ColumnOrder
+	}
+	if dst.ColumnPin != 0 {
+		src.ColumnPin = dst.ColumnPin
+	}
 	if dst.ColumnRainbow {
 		src.ColumnRainbow = dst.ColumnRainbow
 	}
@@ -869,6 +965,9 @@ func mergeGeneral(src general, dst general) general {
 	if dst.FollowName {
 		src.FollowName = dst.FollowName
 	}
+	if dst.JSONMode {
+		src.JSONMode = dst.JSONMode
+	}
 	if dst.ColumnDelimiter != "" {
 		src.ColumnDelimiter = dst.ColumnDelimiter
 	}
@@ -930,7 +1029,7 @@ func (root *Root) docSmall() bool {
 			log.Printf("docSmall %d: %s", y, err)
 			continue
 		}
-		height += 1 + (len(lc) / root.scr.vWidth)
+		height += len(leftX(root.scr.vWidth, lc))
 		if height > root.scr.vHeight {
 			return false
 		}
@@ -38,6 +38,9 @@ func (m *Document) firstRead(reader *bufio.Reader) (*bufio.Reader, error) {
 		return nil, err
 	}
 
+	if m.useIndex() {
+		m.applyIndex()
+	}
 	m.requestContinue()
 	return reader, nil
 }
@@ -68,6 +71,9 @@ func (m *Document) tmpRead(reader *bufio.Reader) (*bufio.Reader, error) {
 // and only reads the file or counts the lines of the file.
 func (m *Document) continueRead(reader *bufio.Reader) (*bufio.Reader, error) {
 	if m.seekable {
+		if err := m.parallelReserve(); err != nil {
+			log.Printf("parallelReserve: %s", err)
+		}
 		if err := m.seekChunk(reader, m.store.offset); err != nil {
 			atomic.StoreInt32(&m.store.eof, 1)
 			log.Printf("continueRead: %s", err)
@@ -98,6 +104,9 @@ func (m *Document) followRead(reader *bufio.Reader) (*bufio.Reader, error) {
 		return reader, nil
 	}
 
+	if m.seekable {
+		m.checkTruncated()
+	}
 	reader, err := m.loadRead(reader, m.store.lastChunkNum())
 	if err != nil {
 		return reader, err
@@ -153,6 +162,9 @@ func (m *Document) reserveChunk(reader *bufio.Reader, start int, end int) error
 // loadChunk actually loads the reserved Chunk.
 func (m *Document) loadChunk(reader *bufio.Reader, chunkNum int) (*bufio.Reader, error) {
 	chunk := m.store.chunks[chunkNum]
+	if m.loadChunkMmap(chunkNum) {
+		return reader, nil
+	}
 	if err := m.seekChunk(reader, chunk.start); err != nil {
 		return nil, err
 	}
@@ -168,6 +180,81 @@ func (m *Document) loadChunk(reader *bufio.Reader, chunkNum int) (*bufio.Reader,
 	return reader, nil
 }
 
+// loadChunkMmap loads the reserved Chunk as slices of the mapped file.
+// It returns false if the file is not mapped, and the chunk is read as usual.
+func (m *Document) loadChunkMmap(chunkNum int) bool {
+	if m.mmap == nil || m.checkTruncated() {
+		return false
+	}
+	start, end := m.store.chunkRange(chunkNum)
+	if m.store.mapLines(m.store.chunks[chunkNum], m.mmap, start, end) {
+		return true
+	}
+	// The file may have grown after mapping.
+	if !m.remap() {
+		return false
+	}
+	return m.store.mapLines(m.store.chunks[chunkNum], m.mmap, start, end)
+}
+
+// remap maps the file if it is not mapped or has grown beyond the mapping.
+// The new mapping is twice as large as the previous one so that a growing file is not mapped every time.
+// Only the part of the file that has been read is accessed, so the mapping beyond the end of the file is not touched.
+func (m *Document) remap() bool {
+	fi, err := m.file.Stat()
+	if err != nil || fi.Size() == 0 {
+		return false
+	}
+	if int64(len(m.mmap)) >= fi.Size() {
+		return false
+	}
+	b, err := mmapFile(m.file, max(fi.Size(), int64(len(m.mmap))*2))
+	if err != nil {
+		log.Printf("mmap: %s", err)
+		return false
+	}
+	m.mmap = b
+	m.mmaps = append(m.mmaps, b)
+	return true
+}
+
+// checkTruncated releases the mapping if the file is smaller than the part that has been read,
+// such as copytruncate of log rotation, because accessing the truncated part of the mapping causes SIGBUS.
+// The mapped lines are unloaded, and the file is read without mapping afterwards.
+func (m *Document) checkTruncated() bool {
+	if m.mmap == nil {
+		return false
+	}
+	fi, err := m.file.Stat()
+	m.store.mu.Lock()
+	defer m.store.mu.Unlock()
+	if err == nil && fi.Size() >= m.store.size {
+		return false
+	}
+	log.Printf("mmap: %s is truncated", m.FileName)
+	m.unmap(false)
+	atomic.StoreInt32(&m.store.changed, 1)
+	m.ClearCache()
+	return true
+}
+
+// unmap unmaps all mappings of the file.
+// The mapped lines are copied if keep is true, otherwise they are unloaded.
+// The caller must hold store.mu.
+func (m *Document) unmap(keep bool) {
+	if m.mmaps == nil {
+		return
+	}
+	m.store.detachMapped(keep)
+	for _, b := range m.mmaps {
+		if err := munmapFile(b); err != nil {
+			log.Printf("munmap: %s", err)
+		}
+	}
+	m.mmap = nil
+	m.mmaps = nil
+}
+
 // seekChunk seeks to the start of the chunk.
 func (m *Document) seekChunk(reader *bufio.Reader, start int64) error {
 	if _, err := m.file.Seek(start, io.SeekStart); err != nil {
@@ -182,7 +269,7 @@ func (m *Document) searchRead(reader *bufio.Reader, chunkNum int, searcher Searc
 	if _, err := m.searchChunk(chunkNum, searcher); err != nil {
 		return reader, err
 	}
-	return m.loadReadFile(reader, chunkNum)
+	return m.loadRead(reader, chunkNum)
 }
 
 // loadRead loads the read contents into chunks.
@@ -207,6 +294,9 @@ func (m *Document) loadReadFile(reader *bufio.Reader, chunkNum int) (*bufio.Read
 // loadReadMem loads the read contents into chunks.
 // loadReadMem frees the memory behind and reads forward.
 func (m *Document) loadReadMem(reader *bufio.Reader, chunkNum int) (*bufio.Reader, error) {
+	if m.isReloadable() {
+		return m.loadReadEvictable(reader, chunkNum)
+	}
 	if m.BufEOF() {
 		return reader, nil
 	}
@@ -221,6 +311,71 @@ func (m *Document) loadReadMem(reader *bufio.Reader, chunkNum int) (*bufio.Reade
 	return reader, nil
 }
 
+// isReloadable returns true if the evicted chunks of the non-seekable document can be reloaded.
+func (m *Document) isReloadable() bool {
+	return m.seekPoints != nil || m.spool != nil || (m.source != nil && m.source.Seekable())
+}
+
+// loadReadEvictable loads the read contents into chunks.
+// Unlike loadReadMem, the evicted chunks are reloaded from the seek points of
+// the compressed file, the spool file or the seekable source.
+func (m *Document) loadReadEvictable(reader *bufio.Reader, chunkNum int) (*bufio.Reader, error) {
+	if chunkNum >= m.store.lastChunkNum() {
+		if m.BufEOF() {
+			return reader, nil
+		}
+		m.store.evictChunksReload(m.spool)
+		m.requestContinue()
+		return reader, nil
+	}
+	if !m.store.isUnloadedChunk(chunkNum) {
+		// already loaded.
+		m.store.loadedChunks.Get(chunkNum)
+		return reader, nil
+	}
+
+	m.store.evictChunksReload(m.spool)
+	if err := m.reloadChunk(chunkNum); err != nil {
+		return reader, err
+	}
+	m.store.loadChunksMem(chunkNum)
+	return reader, nil
+}
+
+// reloadChunk reads the evicted chunk again.
+func (m *Document) reloadChunk(chunkNum int) error {
+	chunk := m.store.chunks[chunkNum]
+	r, err := m.chunkReader(chunkNum)
+	if err != nil {
+		return fmt.Errorf("reload chunk %d: %w", chunkNum, err)
+	}
+	defer r.Close()
+
+	start, end := m.store.chunkRange(chunkNum)
+	if err := m.store.readLines(chunk, bufio.NewReader(r), start, end, false); err != nil && !errors.Is(err, io.EOF) {
+		log.Printf("Failed to read the expected number of lines(%d:%d): %s", start, end, err)
+		return err
+	}
+	return nil
+}
+
+// chunkReader returns a reader from the start of the chunk that is not in memory.
+func (m *Document) chunkReader(chunkNum int) (io.ReadCloser, error) {
+	chunk := m.store.chunks[chunkNum]
+	switch {
+	case m.spool != nil:
+		return m.spool.reader(chunkNum)
+	case m.seekPoints != nil:
+		return m.seekPoints.open(chunk.start)
+	case m.source != nil:
+		return io.NopCloser(io.NewSectionReader(m.source, chunk.start, 1<<63-1-chunk.start)), nil
+	}
+	if _, err := m.file.Seek(chunk.start, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("seek: %w", err)
+	}
+	return io.NopCloser(m.file), nil
+}
+
 // reloadRead performs reload processing.
 func (m *Document) reloadRead(reader *bufio.Reader) (*bufio.Reader, error) {
 	// Add to store in WatchMode, otherwise reset
@@ -264,6 +419,9 @@ func (m *Document) reloadFile(reader *bufio.Reader) (*bufio.Reader, error) {
 // afterEOF does processing after reaching EOF.
 func (m *Document) afterEOF(reader *bufio.Reader) *bufio.Reader {
 	m.store.offset = m.store.size
+	if m.useIndex() {
+		m.saveIndex()
+	}
 	atomic.StoreInt32(&m.store.eof, 1)
 	if atomic.SwapInt32(&m.tmpFollow, 0) == 1 {
 		atomic.StoreInt32(&m.tmpLN, atomic.LoadInt32(&m.followStore.endNum))
@@ -293,10 +451,11 @@ func (m *Document) fileReader(f *os.File) (io.Reader, error) {
 	atomic.StoreInt32(&m.closed, 0)
 	m.file = f
 
+	m.seekPoints = nil
 	cFormat := UNCOMPRESSED
 	r := io.Reader(m.file)
 	if !SkipExtract {
-		cFormat, r = uncompressedReader(m.file, m.seekable)
+		cFormat, r = uncompressedReader(m.FileName, m.file, m.seekable)
 	}
 
 	if cFormat == UNCOMPRESSED {
@@ -306,8 +465,16 @@ func (m *Document) fileReader(f *os.File) (io.Reader, error) {
 				return nil, fmt.Errorf("seek: %w", err)
 			}
 			r = f
+			// The lines of the previous file are kept in WatchMode.
+			m.unmap(true)
+			if MmapFile {
+				m.remap()
+			}
 		}
 	} else {
+		if m.seekable {
+			r = m.compressedFileReader(cFormat, r)
+		}
 		m.seekable = false
 	}
 	m.CFormat = cFormat
@@ -318,6 +485,29 @@ func (m *Document) fileReader(f *os.File) (io.Reader, error) {
 	return r, nil
 }
 
+// compressedFileReader sets the seek points of the compressed regular file
+// so that the chunks evicted from memory can be reloaded.
+// The seek points are not needed if MemoryLimit is not specified, because no chunk is evicted.
+// For gzip, it returns a reader that adds the start of each member as a seek point.
+func (m *Document) compressedFileReader(cFormat Compressed, r io.Reader) io.Reader {
+	if MemoryLimit < 0 {
+		return r
+	}
+	m.seekPoints = newSeekPoints(cFormat, m.file)
+	if cFormat != GZIP {
+		return r
+	}
+	if _, err := m.file.Seek(0, io.SeekStart); err != nil {
+		return r
+	}
+	gr, err := newGzipMembers(m.seekPoints, m.file)
+	if err != nil {
+		log.Printf("gzip: %s", err)
+		return r
+	}
+	return gr
+}
+
 // open opens a file.
 func open(fileName string) (*os.File, error) {
 	if fileName == "" {
@@ -340,7 +530,7 @@ func (m *Document) reload() error {
 	}
 	// Prevent reload if stdin reaches EOF.
 	// Because no more content will be added.
-	if m.FileName == "" && m.BufEOF() {
+	if m.FileName == "" && m.source == nil && m.BufEOF() {
 		return ErrEOFreached
 	}
 
@@ -359,8 +549,12 @@ func (m *Document) reset() {
 	if !m.BufEOF() {
 		return
 	}
+	m.stopMatchCount()
 	m.store = NewStore()
 	m.store.setNewLoadChunks(m.memoryLimit)
+	if m.spool != nil {
+		m.spool.reset()
+	}
 	atomic.StoreInt32(&m.store.changed, 1)
 	m.ClearCache()
 }
@@ -380,6 +574,14 @@ func (m *Document) close() error {
 	if err := m.file.Close(); err != nil {
 		return fmt.Errorf("close: %w", err)
 	}
+	m.store.mu.Lock()
+	m.unmap(false)
+	m.store.mu.Unlock()
+	if m.spool != nil {
+		if err := m.spool.close(); err != nil {
+			log.Printf("spool: %s", err)
+		}
+	}
 	atomic.StoreInt32(&m.store.eof, 1)
 	atomic.StoreInt32(&m.closed, 1)
 	atomic.StoreInt32(&m.store.changed, 1)
@@ -0,0 +1,176 @@
+package oviewer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"runtime"
+	"sync/atomic"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// parallelScanSize is the minimum size of the unread part of the file
+// to count the lines in parallel.
+var parallelScanSize int64 = 16 * 1024 * 1024
+
+// parallelScanMax is the maximum size counted by one parallelReserve call.
+// The rest is counted by the next call so that the control loop
+// can handle other requests in between.
+const parallelScanMax int64 = 1024 * 1024 * 1024
+
+// scanBlockSize is the size of the block to count the lines in parallel.
+const scanBlockSize = 64 * 1024
+
+// boundaryTask is the block containing the chunk boundaries
+// and the line numbers of the boundaries in the block.
+type boundaryTask struct {
+	// needs is the number of newlines (1-origin) in the block that are chunk boundaries.
+	needs []int
+	// block is the block number.
+	block int
+	// index is the index of the first boundary of the task.
+	index int
+}
+
+// parallelReserve reserves chunks by counting lines of the seekable file in parallel.
+// Only complete chunks are reserved, the rest is read by continueRead as usual.
+func (m *Document) parallelReserve() error {
+	s := m.store
+	s.mu.RLock()
+	start := s.size
+	full := int(atomic.LoadInt32(&s.endNum)) == len(s.chunks)*ChunkSize
+	s.mu.RUnlock()
+	if !full || atomic.LoadInt32(&s.noNewlineEOF) == 1 {
+		return nil
+	}
+
+	fi, err := m.file.Stat()
+	if err != nil {
+		return err
+	}
+	end := fi.Size()
+	if end-start < parallelScanSize {
+		return nil
+	}
+	end = min(end, start+parallelScanMax)
+
+	counts, err := countBlocks(m.file, start, end, &s.readCancel)
+	if err != nil {
+		return err
+	}
+	boundaries, err := chunkBoundaries(m.file, start, end, counts, &s.readCancel)
+	if err != nil {
+		return err
+	}
+	if len(boundaries) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	chunkStart := start
+	for _, b := range boundaries {
+		// Reserved chunks are loaded later by loadChunk.
+		s.chunks = append(s.chunks, &chunk{start: chunkStart})
+		chunkStart = b
+	}
+	s.size = chunkStart
+	s.offset = s.size
+	atomic.AddInt32(&s.endNum, int32(len(boundaries)*ChunkSize))
+	atomic.StoreInt32(&s.changed, 1)
+	return nil
+}
+
+// countBlocks counts the newlines of each block from start to end in parallel.
+func countBlocks(r io.ReaderAt, start int64, end int64, cancel *int32) ([]int, error) {
+	nBlocks := int((end - start + scanBlockSize - 1) / scanBlockSize)
+	counts := make([]int, nBlocks)
+	workers := runtime.NumCPU()
+	span := (nBlocks + workers - 1) / workers
+
+	var eg errgroup.Group
+	for w := 0; w < nBlocks; w += span {
+		first, last := w, min(w+span, nBlocks)
+		eg.Go(func() error {
+			buf := make([]byte, scanBlockSize)
+			for b := first; b < last; b++ {
+				if atomic.LoadInt32(cancel) == 1 {
+					return ErrCancel
+				}
+				block, err := readBlock(r, buf, start, end, b)
+				if err != nil {
+					return err
+				}
+				counts[b] = bytes.Count(block, []byte("\n"))
+			}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// chunkBoundaries returns the offsets just after every ChunkSize newlines from start.
+// Only the blocks containing the boundaries are read again in parallel.
+func chunkBoundaries(r io.ReaderAt, start int64, end int64, counts []int, cancel *int32) ([]int64, error) {
+	var tasks []boundaryTask
+	total, lines := 0, 0
+	for b, c := range counts {
+		need := ChunkSize - lines%ChunkSize
+		if need <= c {
+			task := boundaryTask{block: b, index: total}
+			for ; need <= c; need += ChunkSize {
+				task.needs = append(task.needs, need)
+			}
+			total += len(task.needs)
+			tasks = append(tasks, task)
+		}
+		lines += c
+	}
+
+	boundaries := make([]int64, total)
+	var eg errgroup.Group
+	eg.SetLimit(runtime.NumCPU())
+	for _, task := range tasks {
+		task := task
+		eg.Go(func() error {
+			if atomic.LoadInt32(cancel) == 1 {
+				return ErrCancel
+			}
+			buf := make([]byte, scanBlockSize)
+			block, err := readBlock(r, buf, start, end, task.block)
+			if err != nil {
+				return err
+			}
+			offset := start + int64(task.block)*scanBlockSize
+			pos, num := 0, 0
+			for i, need := range task.needs {
+				for num < need {
+					p := bytes.IndexByte(block[pos:], '\n')
+					pos += p + 1
+					num++
+				}
+				boundaries[task.index+i] = offset + int64(pos)
+			}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	return boundaries, nil
+}
+
+// readBlock reads the specified block into buf and returns it.
+func readBlock(r io.ReaderAt, buf []byte, start int64, end int64, block int) ([]byte, error) {
+	offset := start + int64(block)*scanBlockSize
+	size := min(int64(scanBlockSize), end-offset)
+	n, err := r.ReadAt(buf[:size], offset)
+	if int64(n) != size {
+		return nil, fmt.Errorf("read block %d: %w", block, err)
+	}
+	return buf[:n], nil
+}
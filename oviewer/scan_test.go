@@ -0,0 +1,68 @@
+package oviewer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDocument_parallelReserve(t *testing.T) {
+	defer func(size int64) {
+		parallelScanSize = size
+	}(parallelScanSize)
+
+	tests := []struct {
+		name string
+		str  string
+	}{
+		{
+			name: "testNumbers",
+			str:  numberLines(95000),
+		},
+		{
+			name: "testEmptyLines",
+			str:  strings.Repeat("\n", 52345),
+		},
+		{
+			name: "testLongLines",
+			str:  strings.Repeat(strings.Repeat("x", 300)+"\n", 25000),
+		},
+		{
+			name: "testNoNewlineEOF",
+			str:  strings.Repeat("abc\n", 25000) + "end",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileName := filepath.Join(t.TempDir(), "scan.txt")
+			if err := os.WriteFile(fileName, []byte(tt.str), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			parallelScanSize = 1 << 62
+			want := openEOF(t, fileName)
+			parallelScanSize = 0
+			got := openEOF(t, fileName)
+
+			if got.BufEndNum() != want.BufEndNum() {
+				t.Errorf("BufEndNum() = %v, want %v", got.BufEndNum(), want.BufEndNum())
+			}
+			if !reflect.DeepEqual(chunkStarts(got), chunkStarts(want)) {
+				t.Errorf("chunk starts = %v, want %v", chunkStarts(got), chunkStarts(want))
+			}
+			if got.store.size != want.store.size {
+				t.Errorf("size = %v, want %v", got.store.size, want.store.size)
+			}
+		})
+	}
+}
+
+func numberLines(n int) string {
+	var b strings.Builder
+	for i := 1; i <= n; i++ {
+		b.WriteString(strings.Repeat("a", i%97))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -6,7 +6,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"io"
 	"log"
 	"regexp"
 	"strconv"
@@ -51,8 +50,7 @@ func (substr searchWord) MatchString(s string) bool {
 
 // searchWord FindAll searches for strings and returns the index of the match.
 func (substr searchWord) FindAll(s string) [][]int {
-	s = strings.ToLower(s)
-	return allStringIndex(s, substr.word)
+	return allStringIndexFold(s, substr.word)
 }
 
 // searchWord String returns the search word.
@@ -115,23 +113,26 @@ func (substr regexpWord) String() string {
 	return substr.word
 }
 
-// stripRegexpES is a regular expression that excludes escape sequences.
-var stripRegexpES = regexp.MustCompile("(\x1b\\[[\\d;*]*m)|.\b")
-
 // stripEscapeSequenceString strips if it contains escape sequences.
+// The result is the same text as LineC.str,
+// so the matching is the same as the highlighting.
 func stripEscapeSequenceString(s string) string {
+	// The CR of CRLF lines is removed without parsing.
+	s = strings.TrimSuffix(s, "\r")
 	// Remove EscapeSequence.
-	if strings.ContainsAny(s, "\x1b\b") {
-		s = stripRegexpES.ReplaceAllString(s, "")
+	if strings.ContainsAny(s, "\x1b\b\r") {
+		s = stripEscapeSequence(s)
 	}
 	return s
 }
 
 // stripEscapeSequence strips if it contains escape sequences.
 func stripEscapeSequenceBytes(s []byte) []byte {
+	// The CR of CRLF lines is removed without parsing.
+	s = bytes.TrimSuffix(s, []byte("\r"))
 	// Remove EscapeSequence.
-	if bytes.ContainsAny(s, "\x1b\b") {
-		s = stripRegexpES.ReplaceAll(s, []byte(""))
+	if bytes.ContainsAny(s, "\x1b\b\r") {
+		s = []byte(stripEscapeSequence(string(s)))
 	}
 	return s
 }
@@ -242,17 +243,19 @@ func searchPositionStr(caseSensitive bool, s string, substr string) [][]int {
 	}
 
 	if !caseSensitive {
-		s = strings.ToLower(s)
-		substr = strings.ToLower(substr)
+		return allStringIndexFold(s, strings.ToLower(substr))
 	}
 	return allStringIndex(s, substr)
 }
 
 // setSearcher is a wrapper for NewSearcher and returns a Searcher interface.
-// Returns nil if there is no search term.
+// Returns nil if there is no search term, or fuzzy search and boolean search are both enabled.
 func (root *Root) setSearcher(word string, caseSensitive bool) Searcher {
 	if word == "" {
 		root.searcher = nil
+		if root.Doc != nil {
+			root.Doc.stopMatchCount()
+		}
 		return nil
 	}
 	root.input.value = word
@@ -265,9 +268,30 @@ func (root *Root) setSearcher(word string, caseSensitive bool) Searcher {
 			}
 		}
 	}
-	reg := regexpCompile(word, caseSensitive)
-	searcher := NewSearcher(word, reg, caseSensitive, root.Config.RegexpSearch)
+	if root.Config.FuzzySearch && root.Config.BooleanSearch {
+		root.setMessagef("search: %s", ErrSearchConflict.Error())
+		root.searcher = nil
+		if root.Doc != nil {
+			root.Doc.stopMatchCount()
+		}
+		return nil
+	}
+	var searcher Searcher
+	if root.Config.FuzzySearch {
+		searcher = NewFuzzySearcher(word, caseSensitive)
+	} else if root.Config.BooleanSearch {
+		searcher = NewBooleanSearcher(word, caseSensitive, root.Config.RegexpSearch)
+	} else {
+		reg := regexpCompile(word, caseSensitive)
+		searcher = NewSearcher(word, reg, caseSensitive, root.Config.RegexpSearch)
+	}
+	if root.Config.ColumnSearch && root.Doc != nil && root.Doc.ColumnMode {
+		searcher = root.Doc.columnSearcher(searcher)
+	}
 	root.searcher = searcher
+	if root.Doc != nil {
+		root.Doc.startMatchCount(searcher)
+	}
 	return searcher
 }
 
@@ -320,11 +344,14 @@ func (m *Document) Search(ctx context.Context, searcher Searcher, chunkNum int,
 		if chunkNum != 0 && m.store.lastChunkNum() <= chunkNum {
 			m.requestLoad(chunkNum)
 		}
+		if !m.isLoadedChunk(chunkNum) && !m.storageSearch(searcher, chunkNum) {
+			return 0, ErrNotFound
+		}
 	} else {
 		if m.store.lastChunkNum() < chunkNum {
 			return 0, ErrOutOfChunk
 		}
-		if !m.store.isLoadedChunk(chunkNum, m.seekable) && !m.storageSearch(searcher, chunkNum) {
+		if !m.isLoadedChunk(chunkNum) && !m.storageSearch(searcher, chunkNum) {
 			return 0, ErrNotFound
 		}
 	}
@@ -375,7 +402,7 @@ func (m *Document) SearchChunkNonMatch(ctx context.Context, searcher Searcher, c
 
 // BackSearch searches backward from the specified line.
 func (m *Document) BackSearch(ctx context.Context, searcher Searcher, chunkNum int, line int) (int, error) {
-	if !m.store.isLoadedChunk(chunkNum, m.seekable) && !m.storageSearch(searcher, chunkNum) {
+	if !m.isLoadedChunk(chunkNum) && !m.storageSearch(searcher, chunkNum) {
 		return 0, ErrNotFound
 	}
 	if m.nonMatch {
@@ -422,9 +449,18 @@ func (m *Document) BackSearchChunkNonMatch(ctx context.Context, searcher Searche
 	return 0, ErrNotFound
 }
 
+// isLoadedChunk returns true if the chunk is loaded in memory.
+// The chunks of the compressed file may have been evicted even if it is not seekable.
+func (m *Document) isLoadedChunk(chunkNum int) bool {
+	if m.isReloadable() {
+		return !m.store.isUnloadedChunk(chunkNum)
+	}
+	return m.store.isLoadedChunk(chunkNum, m.seekable)
+}
+
 // storageSearch searches for line not in memory(storage).
 func (m *Document) storageSearch(searcher Searcher, chunkNum int) bool {
-	if !m.store.isLoadedChunk(chunkNum, m.seekable) && atomic.LoadInt32(&m.closed) == 0 {
+	if !m.isLoadedChunk(chunkNum) && atomic.LoadInt32(&m.closed) == 0 {
 		if m.requestSearch(chunkNum, searcher) {
 			return true
 		}
@@ -433,11 +469,17 @@ func (m *Document) storageSearch(searcher Searcher, chunkNum int) bool {
 }
 
 // SearchLine searches the document and returns the matching line number.
+// The chunks after the first chunk of the seekable file are searched in parallel.
 func (m *Document) SearchLine(ctx context.Context, searcher Searcher, lineNum int) (int, error) {
 	lineNum = max(lineNum, m.BufStartNum())
 	startChunk, sn := chunkLineNum(lineNum)
 
 	for cn := startChunk; ; cn++ {
+		if cn > startChunk {
+			if m.canSearchParallel() {
+				return m.parallelSearch(ctx, searcher, cn, true)
+			}
+		}
 		n, err := m.Search(ctx, searcher, cn, sn)
 		if err == nil {
 			return cn*ChunkSize + n, nil
@@ -465,6 +507,11 @@ func (m *Document) BackSearchLine(ctx context.Context, searcher Searcher, lineNu
 	startChunk, sn := chunkLineNum(lineNum)
 	minChunk, _ := chunkLineNum(m.BufStartNum())
 	for cn := startChunk; cn >= minChunk; cn-- {
+		if cn < startChunk {
+			if m.canSearchParallel() {
+				return m.parallelSearch(ctx, searcher, cn, false)
+			}
+		}
 		n, err := m.BackSearch(ctx, searcher, cn, sn)
 		if err == nil {
 			return cn*ChunkSize + n, nil
@@ -703,13 +750,14 @@ func (root *Root) sendBackSearch(str string) {
 // searchChunk searches in a Chunk without loading it into memory.
 func (m *Document) searchChunk(chunkNum int, searcher Searcher) (int, error) {
 	// Seek to the start of the chunk.
-	chunk := m.store.chunks[chunkNum]
-	if _, err := m.file.Seek(chunk.start, io.SeekStart); err != nil {
-		return 0, fmt.Errorf("seek: %w", err)
+	r, err := m.chunkReader(chunkNum)
+	if err != nil {
+		return 0, err
 	}
+	defer r.Close()
 
 	// Read the chunk line by line.
-	reader := bufio.NewReader(m.file)
+	reader := bufio.NewReader(r)
 	var line bytes.Buffer
 	var isPrefix bool
 	num := 0
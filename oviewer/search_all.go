@@ -0,0 +1,145 @@
+package oviewer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"unicode/utf8"
+)
+
+// searchAllContext is the number of bytes before the match
+// that are displayed in the search results.
+const searchAllContext = 20
+
+// SearchAll fires the search all event.
+func (root *Root) SearchAll(str string) {
+	root.input.value = str
+	ev := &eventInputSearchAll{
+		value: str,
+	}
+	root.postEvent(ev)
+}
+
+// searchAll collects all the matching lines of the document into a new document.
+// Each line of the new document points to the line of the original document.
+func (root *Root) searchAll(ctx context.Context) {
+	searcher := root.setSearcher(root.input.value, root.Config.CaseSensitive)
+	if searcher == nil {
+		return
+	}
+	word := searcher.String()
+	if root.Doc.nonMatch {
+		word = fmt.Sprintf("!%s", word)
+	}
+
+	m := root.Doc
+	r, w := io.Pipe()
+	resultDoc, err := renderDoc(m, r)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	resultDoc.documentType = DocSearchAll
+	resultDoc.FileName = fmt.Sprintf("search:%s:%v", m.FileName, word)
+	resultDoc.Caption = fmt.Sprintf("%s:%v", m.FileName, word)
+	root.addDocument(resultDoc.Document)
+	resultDoc.Document.general = mergeGeneral(m.general, resultDoc.Document.general)
+	// The results have no header, and the line number prefix breaks the columns.
+	resultDoc.Header = 0
+	resultDoc.SkipLines = 0
+	resultDoc.ColumnMode = false
+
+	resultDoc.nonMatch = m.nonMatch
+	resultDoc.writer = w
+	go m.searchAllWriter(ctx, searcher, resultDoc)
+	root.setMessagef("search all:%v%s", word, root.parentJumpHint())
+}
+
+// searchAllWriter searches the document and writes the matching lines
+// with the line number to the result document.
+func (m *Document) searchAllWriter(ctx context.Context, searcher Searcher, resultDoc *renderDocument) {
+	defer resultDoc.writer.Close()
+	for originLN, renderLN := m.firstLine(), 0; ; renderLN++ {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+		lineNum, err := m.searchLine(ctx, searcher, true, originLN)
+		if err != nil {
+			// Not found
+			return
+		}
+		line, err := m.matchedLine(lineNum)
+		if err != nil {
+			return
+		}
+		num := lineNum
+		if m.lineNumMap != nil {
+			if n, ok := m.lineNumMap.LoadForward(num); ok {
+				num = n
+			}
+		}
+		resultDoc.lineNumMap.Store(renderLN, num)
+		resultDoc.writeLine([]byte(searchAllLine(searcher, num-m.firstLine()+1, string(line))))
+		originLN = lineNum + 1
+	}
+}
+
+// searchAllLine returns the line of the search results.
+// The line is prefixed with the line number,
+// and the beginning of a line is omitted if the match is far from it.
+func searchAllLine(searcher Searcher, num int, str string) string {
+	str = stripEscapeSequenceString(str)
+	if pos := searcher.FindAll(str); len(pos) > 0 && pos[0][0] > searchAllContext {
+		start := pos[0][0] - searchAllContext
+		for start < len(str) && !utf8.RuneStart(str[start]) {
+			start++
+		}
+		str = "..." + str[start:]
+	}
+	return fmt.Sprintf("%d:%s", num, str)
+}
+
+// parentJumpHint returns the hint of the keys to jump to the original document.
+// It returns an empty string if no key is bound.
+func (root *Root) parentJumpHint() string {
+	if len(root.parentJumpKeys) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%s)Jump", strings.Join(root.parentJumpKeys, ","))
+}
+
+// parentJump switches to the original document at the line of the search all,
+// sort or filter result.
+// The line is the jump target line (the top line if the jump target is not set).
+func (root *Root) parentJump() {
+	m := root.Doc
+	if m.parent == nil || m.lineNumMap == nil {
+		root.setMessage("no original document")
+		return
+	}
+	l := root.scr.lineNumber(m.headerLen + m.jumpTargetNum)
+	lineNum, ok := m.lineNumMap.LoadForward(l.number)
+	if !ok {
+		return
+	}
+
+	docNum := -1
+	root.mu.RLock()
+	for n, doc := range root.DocList {
+		if doc == m.parent {
+			docNum = n
+			break
+		}
+	}
+	root.mu.RUnlock()
+	if docNum < 0 {
+		root.setMessage("the original document is closed")
+		return
+	}
+	root.switchDocument(docNum)
+	root.sendGoto(lineNum - root.Doc.firstLine() + 1)
+}
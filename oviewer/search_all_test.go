@@ -0,0 +1,151 @@
+package oviewer
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func Test_searchAllLine(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		word string
+		num  int
+		str  string
+		want string
+	}{
+		{
+			name: "testShort",
+			word: "error",
+			num:  12,
+			str:  "an error occurred",
+			want: "12:an error occurred",
+		},
+		{
+			name: "testOmit",
+			word: "error",
+			num:  3,
+			str:  "0123456789012345678901234567890123456789 error",
+			want: "3:...1234567890123456789 error",
+		},
+		{
+			name: "testEscape",
+			word: "error",
+			num:  1,
+			str:  "\x1b[31merror\x1b[m",
+			want: "1:error",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			searcher := NewSearcher(tt.word, nil, false, false)
+			if got := searchAllLine(searcher, tt.num, tt.str); got != tt.want {
+				t.Errorf("searchAllLine() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocument_searchAllWriter(t *testing.T) {
+	t.Parallel()
+	m := openEOF(t, parallelSearchFile(t, 30000))
+	r, w := io.Pipe()
+	resultDoc, err := renderDoc(m, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resultDoc.writer = w
+	searcher := NewSearcher("line 2999", nil, false, false)
+	m.searchAllWriter(context.Background(), searcher, resultDoc)
+	for !resultDoc.BufEOF() {
+	}
+
+	want := []struct {
+		str    string
+		origin int
+	}{
+		{str: "3000:line 2999", origin: 2999},
+		{str: "29991:line 29990", origin: 29990},
+		{str: "30000:line 29999", origin: 29999},
+	}
+	if resultDoc.BufEndNum() != 11 {
+		t.Fatalf("BufEndNum() = %d, want 11", resultDoc.BufEndNum())
+	}
+	for _, n := range []int{0, 1, 10} {
+		w := want[min(n, 2)]
+		if got := resultDoc.LineString(n); got != w.str {
+			t.Errorf("LineString(%d) = %q, want %q", n, got, w.str)
+		}
+		if got, _ := resultDoc.lineNumMap.LoadForward(n); got != w.origin {
+			t.Errorf("lineNumMap(%d) = %d, want %d", n, got, w.origin)
+		}
+	}
+}
+
+func TestRoot_parentJump(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root, err := Open(filepath.Join(testdata, "test.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	root.Screen = screen
+	parent := root.Doc
+	r, w := io.Pipe()
+	resultDoc, err := renderDoc(parent, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	resultDoc.lineNumMap.Store(0, 10)
+	resultDoc.lineNumMap.Store(1, 20)
+	root.addDocument(resultDoc.Document)
+
+	// The jump target line is the second line.
+	resultDoc.jumpTargetNum = 1
+	root.scr.numbers = []LineNumber{{0, 0}, {1, 0}, {2, 0}}
+	root.parentJump()
+	if root.Doc != parent {
+		t.Fatal("parentJump() did not switch to the original document")
+	}
+	ev, ok := screen.PollEvent().(*eventGoto)
+	if !ok {
+		t.Fatal("parentJump() did not send eventGoto")
+	}
+	if ev.value != "21" {
+		t.Errorf("parentJump() goto = %s, want 21", ev.value)
+	}
+}
+
+func TestRoot_parentJumpHint(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root, err := Open(filepath.Join(testdata, "test.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := root.setKeyConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := root.parentJumpHint(), " (o)Jump"; got != want {
+		t.Errorf("parentJumpHint() = %q, want %q", got, want)
+	}
+	root.parentJumpKeys = nil
+	if got := root.parentJumpHint(); got != "" {
+		t.Errorf("parentJumpHint() = %q, want empty", got)
+	}
+}
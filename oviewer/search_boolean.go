@@ -0,0 +1,231 @@
+package oviewer
+
+import (
+	"sort"
+	"strings"
+)
+
+// Boolean search operators.
+// NOT binds tighter than AND, and AND binds tighter than OR.
+const (
+	booleanAnd = '&'
+	booleanOr  = '|'
+	booleanNot = '!'
+)
+
+// booleanTerm is a term of the boolean search.
+type booleanTerm struct {
+	searcher Searcher
+	not      bool
+}
+
+// booleanWord is a search that combines terms with AND, OR and NOT.
+// groups are ORed, and the terms in a group are ANDed.
+type booleanWord struct {
+	word   string
+	groups [][]booleanTerm
+}
+
+// booleanWord Match is a boolean search for bytes.
+func (substr booleanWord) Match(s []byte) bool {
+	for _, group := range substr.groups {
+		if matchGroup(group, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// booleanWord MatchString is a boolean search for string.
+func (substr booleanWord) MatchString(s string) bool {
+	for _, group := range substr.groups {
+		if matchGroupString(group, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// booleanWord FindAll returns the index of the terms that matched
+// if the string matches. NOT terms are not included.
+func (substr booleanWord) FindAll(s string) [][]int {
+	var indexes [][]int
+	for _, group := range substr.groups {
+		if !matchGroupString(group, s) {
+			continue
+		}
+		for _, term := range group {
+			if !term.not {
+				indexes = append(indexes, term.searcher.FindAll(s)...)
+			}
+		}
+	}
+	sort.Slice(indexes, func(i, j int) bool {
+		return indexes[i][0] < indexes[j][0]
+	})
+	return indexes
+}
+
+// booleanWord String returns the search word.
+func (substr booleanWord) String() string {
+	return substr.word
+}
+
+// matchGroup returns true if all terms in the group match.
+func matchGroup(group []booleanTerm, s []byte) bool {
+	for _, term := range group {
+		if term.searcher.Match(s) == term.not {
+			return false
+		}
+	}
+	return true
+}
+
+// matchGroupString returns true if all terms in the group match.
+func matchGroupString(group []booleanTerm, s string) bool {
+	for _, term := range group {
+		if term.searcher.MatchString(s) == term.not {
+			return false
+		}
+	}
+	return true
+}
+
+// NewBooleanSearcher returns the Searcher interface for the query
+// that combines terms with & (AND), | (OR) and ! (NOT).
+// e.g. "error & !timeout | panic".
+// Each term is searched in the same way as NewSearcher.
+// The operator characters can be escaped with a backslash.
+// In regular expression search, the operators in parentheses and brackets
+// are a part of the regular expression, e.g. "(a|b)c & !d".
+func NewBooleanSearcher(query string, caseSensitive bool, regexpSearch bool) Searcher {
+	newTerm := func(word string) Searcher {
+		return NewSearcher(word, regexpCompile(word, caseSensitive), caseSensitive, regexpSearch)
+	}
+
+	var groups [][]booleanTerm
+	for _, words := range parseBooleanQuery(query, regexpSearch) {
+		group := make([]booleanTerm, 0, len(words))
+		for _, w := range words {
+			if w.word == "" {
+				continue
+			}
+			group = append(group, booleanTerm{searcher: newTerm(w.word), not: w.not})
+		}
+		if len(group) > 0 {
+			groups = append(groups, group)
+		}
+	}
+
+	switch {
+	case len(groups) == 0:
+		return newTerm(query)
+	case len(groups) == 1 && len(groups[0]) == 1 && !groups[0][0].not:
+		// A single term is the same as a normal search.
+		return groups[0][0].searcher
+	}
+	return booleanWord{
+		word:   query,
+		groups: groups,
+	}
+}
+
+// queryTerm is a term of the parsed query.
+type queryTerm struct {
+	word string
+	not  bool
+}
+
+// parseBooleanQuery splits the query into OR groups of AND terms.
+// The terms are trimmed, and the escaped operators are unescaped.
+// If regexpSearch is true, the escapes are kept for the regular expression,
+// and the operators in parentheses and brackets are not split.
+func parseBooleanQuery(query string, regexpSearch bool) [][]queryTerm {
+	var groups [][]queryTerm
+	var terms []queryTerm
+	var term strings.Builder
+	not := false
+	// depth is the nesting of the parentheses, and class is true in the brackets.
+	depth := 0
+	class := false
+	addTerm := func() {
+		terms = append(terms, queryTerm{word: strings.TrimSpace(term.String()), not: not})
+		term.Reset()
+		not = false
+	}
+
+	runes := []rune(query)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if regexpSearch {
+			switch {
+			case r == '\\':
+				// The escaped character is a literal of the regular expression.
+				term.WriteRune(r)
+				if i+1 < len(runes) {
+					i++
+					term.WriteRune(runes[i])
+				}
+				continue
+			case class:
+				if r == ']' {
+					class = false
+				}
+				term.WriteRune(r)
+				continue
+			case r == '[':
+				class = true
+				term.WriteRune(r)
+				// The "]" at the beginning of the class is a literal.
+				if i+1 < len(runes) && runes[i+1] == '^' {
+					i++
+					term.WriteRune(runes[i])
+				}
+				if i+1 < len(runes) && runes[i+1] == ']' {
+					i++
+					term.WriteRune(runes[i])
+				}
+				continue
+			case r == '(':
+				depth++
+			case r == ')' && depth > 0:
+				depth--
+			case depth > 0:
+				term.WriteRune(r)
+				continue
+			}
+		}
+		switch r {
+		case '\\':
+			// Only the operators are unescaped, so that the escape of the regular expression remains.
+			if i+1 < len(runes) && isBooleanOperator(runes[i+1]) {
+				i++
+				r = runes[i]
+			}
+			term.WriteRune(r)
+		case booleanAnd:
+			addTerm()
+		case booleanOr:
+			addTerm()
+			groups = append(groups, terms)
+			terms = nil
+		case booleanNot:
+			// NOT is an operator only before the term.
+			if strings.TrimSpace(term.String()) == "" {
+				not = !not
+				term.Reset()
+				continue
+			}
+			term.WriteRune(r)
+		default:
+			term.WriteRune(r)
+		}
+	}
+	addTerm()
+	return append(groups, terms)
+}
+
+// isBooleanOperator returns true if r is an operator of the boolean search.
+func isBooleanOperator(r rune) bool {
+	return r == booleanAnd || r == booleanOr || r == booleanNot
+}
@@ -0,0 +1,257 @@
+package oviewer
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestNewBooleanSearcher_Match(t *testing.T) {
+	t.Parallel()
+	type args struct {
+		query        string
+		regexpSearch bool
+	}
+	tests := []struct {
+		name string
+		args args
+		s    string
+		want bool
+	}{
+		{
+			name: "testAnd",
+			args: args{query: "error & disk"},
+			s:    "ERROR: disk full",
+			want: true,
+		},
+		{
+			name: "testAndFalse",
+			args: args{query: "error & network"},
+			s:    "ERROR: disk full",
+			want: false,
+		},
+		{
+			name: "testNot",
+			args: args{query: "error & !timeout"},
+			s:    "error: timeout",
+			want: false,
+		},
+		{
+			name: "testOr",
+			args: args{query: "error & !timeout | panic"},
+			s:    "panic: timeout",
+			want: true,
+		},
+		{
+			name: "testNotOnly",
+			args: args{query: "!debug"},
+			s:    "info: start",
+			want: true,
+		},
+		{
+			name: "testEscape",
+			args: args{query: `a \& b`},
+			s:    "a & b",
+			want: true,
+		},
+		{
+			name: "testEscapeFalse",
+			args: args{query: `a \& b`},
+			s:    "a b",
+			want: false,
+		},
+		{
+			name: "testRegexp",
+			args: args{query: `^\d+ & !^0`, regexpSearch: true},
+			s:    "123 test",
+			want: true,
+		},
+		{
+			name: "testRegexpNot",
+			args: args{query: `^\d+ & !^0`, regexpSearch: true},
+			s:    "0123 test",
+			want: false,
+		},
+		{
+			name: "testRegexpAlternation",
+			args: args{query: `(a|b)c & !d`, regexpSearch: true},
+			s:    "bc",
+			want: true,
+		},
+		{
+			name: "testRegexpAlternationNot",
+			args: args{query: `(a|b)c & !d`, regexpSearch: true},
+			s:    "bcd",
+			want: false,
+		},
+		{
+			name: "testEscapeSequences",
+			args: args{query: "test & !m"},
+			s:    "\x1B[31mtest\x1B[0m",
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			searcher := NewBooleanSearcher(tt.args.query, false, tt.args.regexpSearch)
+			if got := searcher.MatchString(tt.s); got != tt.want {
+				t.Errorf("MatchString() = %v, want %v", got, tt.want)
+			}
+			if got := searcher.Match([]byte(tt.s)); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewBooleanSearcher_FindAll(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		query string
+		s     string
+		want  [][]int
+	}{
+		{
+			name:  "testAnd",
+			query: "disk & error",
+			s:     "error: disk full",
+			want:  [][]int{{0, 5}, {7, 11}},
+		},
+		{
+			name:  "testNotMatch",
+			query: "error & !disk",
+			s:     "error: disk full",
+			want:  nil,
+		},
+		{
+			name:  "testOr",
+			query: "full | network",
+			s:     "error: disk full",
+			want:  [][]int{{12, 16}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			searcher := NewBooleanSearcher(tt.query, false, false)
+			if got := searcher.FindAll(tt.s); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindAll() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewBooleanSearcher_single(t *testing.T) {
+	t.Parallel()
+	want := searchWord{word: "test"}
+	if got := NewBooleanSearcher(" Test ", false, false); !reflect.DeepEqual(got, want) {
+		t.Errorf("NewBooleanSearcher() = %#v, want %#v", got, want)
+	}
+}
+
+func Test_parseBooleanQuery(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		query        string
+		regexpSearch bool
+		want         [][]queryTerm
+	}{
+		{
+			name:  "testSingle",
+			query: "test",
+			want:  [][]queryTerm{{{word: "test"}}},
+		},
+		{
+			name:  "testOperators",
+			query: "a & ! b | c",
+			want: [][]queryTerm{
+				{{word: "a"}, {word: "b", not: true}},
+				{{word: "c"}},
+			},
+		},
+		{
+			name:  "testDoubleNot",
+			query: "!!a",
+			want:  [][]queryTerm{{{word: "a"}}},
+		},
+		{
+			name:  "testNotInTerm",
+			query: "a!b",
+			want:  [][]queryTerm{{{word: "a!b"}}},
+		},
+		{
+			name:  "testEscape",
+			query: `\!a \| b\d`,
+			want:  [][]queryTerm{{{word: `!a | b\d`}}},
+		},
+		{
+			name:         "testRegexpParen",
+			query:        "(a|b)c & !d",
+			regexpSearch: true,
+			want:         [][]queryTerm{{{word: "(a|b)c"}, {word: "d", not: true}}},
+		},
+		{
+			name:         "testRegexpClass",
+			query:        "[|&!] | [^]|]x",
+			regexpSearch: true,
+			want: [][]queryTerm{
+				{{word: "[|&!]"}},
+				{{word: "[^]|]x"}},
+			},
+		},
+		{
+			name:         "testRegexpEscape",
+			query:        `\(a \| b\) | c`,
+			regexpSearch: true,
+			want: [][]queryTerm{
+				{{word: `\(a \| b\)`}},
+				{{word: "c"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := parseBooleanQuery(tt.query, tt.regexpSearch); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseBooleanQuery() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocument_searchLineBoolean(t *testing.T) {
+	t.Parallel()
+	m, err := NewSourceDocument(NewBytesSource([]byte("khaki plum\nkhaki\nplum\nkhaki teal\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for !m.BufEOF() {
+	}
+	tests := []struct {
+		query   string
+		forward bool
+		lineNum int
+		want    int
+	}{
+		{query: "khaki & !plum", forward: true, lineNum: 0, want: 1},
+		{query: "khaki & !plum", forward: true, lineNum: 2, want: 3},
+		{query: "teal | plum & !khaki", forward: true, lineNum: 0, want: 2},
+		{query: "khaki & plum", forward: false, lineNum: 3, want: 0},
+	}
+	for _, tt := range tests {
+		searcher := NewBooleanSearcher(tt.query, false, false)
+		got, err := m.searchLine(context.Background(), searcher, tt.forward, tt.lineNum)
+		if err != nil {
+			t.Fatalf("searchLine(%q) error = %v", tt.query, err)
+		}
+		if got != tt.want {
+			t.Errorf("searchLine(%q, %d) = %d, want %d", tt.query, tt.lineNum, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package oviewer
+
+import (
+	"regexp"
+)
+
+// columnWord is a search only in the column.
+// The column is separated by the delimiter, or by the widths if widths is not nil.
+// If key is not empty, the column is the value of the key of logfmt.
+// If quote is true, the delimiters in the quoted fields of CSV are not separators.
+type columnWord struct {
+	searcher     Searcher
+	column       int
+	delimiter    string
+	delimiterReg *regexp.Regexp
+	widths       []int
+	tabWidth     int
+	key          string
+	quote        bool
+}
+
+// columnSearcher returns the Searcher that searches only in the column of the cursor.
+func (m *Document) columnSearcher(searcher Searcher) Searcher {
+	cw, ok := m.cursorColumn()
+	if !ok {
+		return searcher
+	}
+	cw.searcher = searcher
+	return cw
+}
+
+// cursorColumn returns the columnWord of the column of the cursor without the searcher.
+// It returns false if the column cannot be determined.
+func (m *Document) cursorColumn() (columnWord, bool) {
+	cw := columnWord{
+		column:       m.rawColumn(m.columnCursor),
+		delimiter:    m.ColumnDelimiter,
+		delimiterReg: m.ColumnDelimiterReg,
+		tabWidth:     m.TabWidth,
+		quote:        m.ColumnCSV,
+	}
+	if cw.column < 0 {
+		return cw, false
+	}
+	if m.ColumnLogfmt {
+		if m.columnCursor >= len(m.logfmtKeys) {
+			return cw, false
+		}
+		cw.key = m.logfmtKeys[m.columnCursor]
+		return cw, true
+	}
+	if m.ColumnWidth {
+		if len(m.columnWidths) == 0 {
+			return cw, false
+		}
+		cw.widths = m.columnWidths
+	}
+	return cw, true
+}
+
+// columnWord Match is a search in the column for bytes.
+func (substr columnWord) Match(s []byte) bool {
+	return substr.MatchString(string(s))
+}
+
+// columnWord MatchString is a search in the column for string.
+func (substr columnWord) MatchString(s string) bool {
+	str := substr.columnString(stripEscapeSequenceString(s))
+	start, end := substr.columnRange(str)
+	if start < 0 {
+		return false
+	}
+	return substr.searcher.MatchString(str[start:end])
+}
+
+// columnWord FindAll returns the index of the match in the column.
+func (substr columnWord) FindAll(s string) [][]int {
+	start, end := substr.columnRange(s)
+	if start < 0 {
+		return nil
+	}
+	indexes := substr.searcher.FindAll(s[start:end])
+	for _, index := range indexes {
+		index[0] += start
+		index[1] += start
+	}
+	return indexes
+}
+
+// columnValue returns the value of the column without the surrounding spaces.
+// It returns an empty string if the line does not have the column.
+func (substr columnWord) columnValue(s string) string {
+	str := substr.columnString(stripEscapeSequenceString(s))
+	start, end := substr.columnRange(str)
+	if start < 0 {
+		return ""
+	}
+	return str[start:end]
+}
+
+// columnWord String returns the search word.
+func (substr columnWord) String() string {
+	return substr.searcher.String()
+}
+
+// columnString returns the string as displayed if the columns are separated by widths.
+// The widths are the positions on the screen, so the tabs must be expanded.
+func (substr columnWord) columnString(s string) string {
+	if substr.widths == nil {
+		return s
+	}
+	str, _ := ContentsToStr(parseString(s, substr.tabWidth))
+	return str
+}
+
+// columnRange returns the byte range of the column without the surrounding spaces.
+// It returns -1 if the line does not have the column.
+func (substr columnWord) columnRange(s string) (int, int) {
+	var start, end int
+	if substr.key != "" {
+		start, end = logfmtColumnRange(s, substr.key)
+	} else if substr.widths != nil {
+		start, end = widthColumnRange(s, substr.widths, substr.column, substr.tabWidth)
+	} else {
+		indexes := allIndex(s, substr.delimiter, substr.delimiterReg)
+		if substr.quote {
+			indexes = unquotedIndex(s, indexes)
+		}
+		start, end = indexColumnRange(s, indexes, substr.column)
+	}
+	if start < 0 {
+		return start, end
+	}
+	for start < end && s[start] == ' ' {
+		start++
+	}
+	for end > start && s[end-1] == ' ' {
+		end--
+	}
+	return start, end
+}
+
+// indexColumnRange returns the byte range of the column separated by the indexes of the delimiters.
+// The delimiter at the beginning of the line is not a column separator.
+func indexColumnRange(s string, indexes [][]int, column int) (int, int) {
+	lStart := 0
+	if len(indexes) > 0 && indexes[0][0] == 0 {
+		lStart = indexes[0][1]
+		indexes = indexes[1:]
+	}
+	switch {
+	case column < 0 || column > len(indexes):
+		return -1, -1
+	case len(indexes) == 0:
+		return lStart, len(s)
+	case column == 0:
+		return lStart, indexes[0][0]
+	case column == len(indexes):
+		return indexes[column-1][1], len(s)
+	}
+	return indexes[column-1][1], indexes[column][0]
+}
+
+// widthColumnRange returns the byte range of the column separated by the widths.
+// The range is the same as widthColumnRanges of the column highlight,
+// so the value that extends beyond the column position is included in the column.
+// It returns -1 if the line ends before the column.
+func widthColumnRange(s string, widths []int, column int, tabWidth int) (int, int) {
+	lc := parseString(s, tabWidth)
+	ranges := widthColumnRanges(lc, widths)
+	if column < 0 || column >= len(ranges) {
+		return -1, -1
+	}
+	iStart, iEnd := ranges[column][0], ranges[column][1]
+	if column > 0 && iStart >= len(lc) {
+		return -1, -1
+	}
+
+	_, pos := ContentsToStr(lc)
+	start, end := len(s), len(s)
+	for i := len(pos) - 1; i >= 0; i-- {
+		if pos[i] >= iStart {
+			start = i
+		}
+		if pos[i] >= iEnd {
+			end = i
+		}
+	}
+	return min(start, len(s)), min(end, len(s))
+}
@@ -0,0 +1,215 @@
+package oviewer
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func Test_indexColumnRange(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		s            string
+		delimiter    string
+		delimiterReg *regexp.Regexp
+		column       int
+		wantStart    int
+		wantEnd      int
+	}{
+		{
+			name:      "testFirst",
+			s:         "a,bb,ccc",
+			delimiter: ",",
+			column:    0,
+			wantStart: 0,
+			wantEnd:   1,
+		},
+		{
+			name:      "testMiddle",
+			s:         "a,bb,ccc",
+			delimiter: ",",
+			column:    1,
+			wantStart: 2,
+			wantEnd:   4,
+		},
+		{
+			name:      "testLast",
+			s:         "a,bb,ccc",
+			delimiter: ",",
+			column:    2,
+			wantStart: 5,
+			wantEnd:   8,
+		},
+		{
+			name:      "testOutOfRange",
+			s:         "a,bb,ccc",
+			delimiter: ",",
+			column:    3,
+			wantStart: -1,
+			wantEnd:   -1,
+		},
+		{
+			name:      "testFence",
+			s:         "|a|bb|",
+			delimiter: "|",
+			column:    1,
+			wantStart: 3,
+			wantEnd:   5,
+		},
+		{
+			name:         "testRegexp",
+			s:            "a  bb ccc",
+			delimiterReg: regexp.MustCompile(`\s+`),
+			column:       2,
+			wantStart:    6,
+			wantEnd:      9,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			indexes := allIndex(tt.s, tt.delimiter, tt.delimiterReg)
+			gotStart, gotEnd := indexColumnRange(tt.s, indexes, tt.column)
+			if gotStart != tt.wantStart || gotEnd != tt.wantEnd {
+				t.Errorf("indexColumnRange() = %d, %d, want %d, %d", gotStart, gotEnd, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func Test_columnWord(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		word        string
+		widths      []int
+		quote       bool
+		column      int
+		s           string
+		wantMatch   bool
+		wantIndexes [][]int
+	}{
+		{
+			name:        "testMatch",
+			word:        "500",
+			column:      2,
+			s:           "GET,/500,500,10",
+			wantMatch:   true,
+			wantIndexes: [][]int{{9, 12}},
+		},
+		{
+			name:        "testOtherColumn",
+			word:        "500",
+			column:      2,
+			s:           "GET,/500,200,10",
+			wantMatch:   false,
+			wantIndexes: nil,
+		},
+		{
+			name:        "testAnchor",
+			word:        "^500$",
+			column:      1,
+			s:           "GET, 500 ,1500",
+			wantMatch:   true,
+			wantIndexes: [][]int{{5, 8}},
+		},
+		{
+			name:        "testQuote",
+			word:        "John",
+			quote:       true,
+			column:      1,
+			s:           `1,"Smith, John",3`,
+			wantMatch:   true,
+			wantIndexes: [][]int{{10, 14}},
+		},
+		{
+			name:        "testQuoteOff",
+			word:        "John",
+			column:      1,
+			s:           `1,"Smith, John",3`,
+			wantMatch:   false,
+			wantIndexes: nil,
+		},
+		{
+			name:        "testWidth",
+			word:        "run",
+			widths:      []int{4, 10},
+			column:      1,
+			s:           "app   running 1d",
+			wantMatch:   true,
+			wantIndexes: [][]int{{6, 9}},
+		},
+		{
+			name:        "testWidthOtherColumn",
+			word:        "1d",
+			widths:      []int{4, 10},
+			column:      1,
+			s:           "app   Running 1d",
+			wantMatch:   false,
+			wantIndexes: nil,
+		},
+		{
+			name:        "testWidthShortLine",
+			word:        "^$",
+			widths:      []int{4, 10},
+			column:      2,
+			s:           "app",
+			wantMatch:   false,
+			wantIndexes: nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			searcher := columnWord{
+				searcher:  NewSearcher(tt.word, regexpCompile(tt.word, false), false, true),
+				column:    tt.column,
+				delimiter: ",",
+				widths:    tt.widths,
+				tabWidth:  8,
+				quote:     tt.quote,
+			}
+			if got := searcher.Match([]byte(tt.s)); got != tt.wantMatch {
+				t.Errorf("Match() = %v, want %v", got, tt.wantMatch)
+			}
+			if got := searcher.FindAll(tt.s); !reflect.DeepEqual(got, tt.wantIndexes) {
+				t.Errorf("FindAll() = %v, want %v", got, tt.wantIndexes)
+			}
+		})
+	}
+}
+
+func Test_widthColumnRange(t *testing.T) {
+	t.Parallel()
+	widths := []int{4, 10}
+	tests := []struct {
+		name string
+		s    string
+	}{
+		{
+			name: "testNormal",
+			s:    "app   running 1d",
+		},
+		{
+			name: "testOverflow",
+			s:    "application running 1d",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			// The search range is the same as the range of the column highlight.
+			ranges := widthColumnRanges(parseString(tt.s, 8), widths)
+			for column, r := range ranges {
+				start, end := widthColumnRange(tt.s, widths, column, 8)
+				if start != r[0] || end != r[1] {
+					t.Errorf("widthColumnRange(%d) = %d, %d, want %d, %d", column, start, end, r[0], r[1])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,289 @@
+package oviewer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// matchCountInterval is the interval to wait for lines to be read while counting matches.
+const matchCountInterval = 100 * time.Millisecond
+
+// matchCounter counts the matching lines of the document in the background.
+// The number of matches is kept for each chunk, and the index of the current match
+// is calculated from the counts of the previous chunks.
+type matchCounter struct {
+	// key identifies the searcher being counted.
+	key    string
+	cancel context.CancelFunc
+
+	mu sync.RWMutex
+	// counts is the number of matching lines for each counted chunk.
+	counts []int
+	// partials is true for the chunks counted with lines evicted from memory.
+	partials []bool
+	// counted is the number of chunks whose count is complete.
+	counted int
+	// lines is the number of lines counted when all lines have been counted.
+	lines int
+	// done is true if all lines read so far have been counted.
+	done bool
+	// currentChunk is the chunk of the current match, -1 if there is no current match.
+	currentChunk int
+	// currentIndex is the number of matches in currentChunk up to the current match.
+	currentIndex int
+	// currentGen is incremented each time the current match is set,
+	// so that the result of the previous current match is discarded.
+	currentGen int
+	// currentCancel cancels the count of the previous current match.
+	currentCancel context.CancelFunc
+}
+
+// matchCounterKey returns the key that identifies the searcher.
+// The count is restarted when the key changes.
+func matchCounterKey(searcher Searcher, nonMatch bool) string {
+	key := fmt.Sprintf("%T:%t:%s", searcher, nonMatch, searcher.String())
+	if cw, ok := searcher.(columnWord); ok {
+		key = fmt.Sprintf("%s:%T:%d", key, cw.searcher, cw.column)
+	}
+	return key
+}
+
+// startMatchCount starts counting the lines that match the searcher in the background.
+// The previous count is cancelled if the searcher has changed.
+func (m *Document) startMatchCount(searcher Searcher) {
+	if searcher == nil {
+		m.stopMatchCount()
+		return
+	}
+	key := matchCounterKey(searcher, m.nonMatch)
+	if mc := m.matchCount.Load(); mc != nil && mc.key == key {
+		return
+	}
+	m.stopMatchCount()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mc := &matchCounter{
+		key:          key,
+		cancel:       cancel,
+		currentChunk: -1,
+	}
+	m.matchCount.Store(mc)
+	go m.countMatches(ctx, mc, searcher)
+}
+
+// stopMatchCount cancels the count.
+// It is called when the search is cleared, the document is switched or its lines are reset.
+func (m *Document) stopMatchCount() {
+	mc := m.matchCount.Swap(nil)
+	if mc == nil {
+		return
+	}
+	mc.cancel()
+	mc.mu.Lock()
+	if mc.currentCancel != nil {
+		mc.currentCancel()
+	}
+	mc.mu.Unlock()
+}
+
+// countMatches counts the matching lines chunk by chunk.
+// The last chunk is counted when the document reaches EOF,
+// and is counted again when lines are added.
+func (m *Document) countMatches(ctx context.Context, mc *matchCounter, searcher Searcher) {
+	f := m.countFile()
+
+	timer := time.NewTicker(matchCountInterval)
+	defer timer.Stop()
+	for cn := 0; ; {
+		if m.checkClose() {
+			return
+		}
+		lastChunk := m.store.lastChunkNum()
+		endNum := m.BufEndNum()
+		if cn < lastChunk || (m.BufEOF() && !mc.isCounted(endNum)) {
+			count, err := m.countChunk(ctx, f, searcher, cn)
+			partial := errors.Is(err, ErrEvictedMemory)
+			if err != nil && !partial {
+				return
+			}
+			mc.setCount(cn, count, partial, cn == lastChunk, endNum)
+			atomic.StoreInt32(&m.store.changed, 1)
+			if cn < lastChunk {
+				cn++
+			}
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// countFile returns the open file to read the chunks with ReadAt.
+// The file is not reopened by name, because it may have been renamed or rotated.
+// It returns nil if the document is not seekable, and the lines in memory are counted.
+func (m *Document) countFile() io.ReaderAt {
+	if !m.seekable || m.file == nil {
+		return nil
+	}
+	return m.file
+}
+
+// countChunk returns the number of matching lines in the chunk.
+// It returns ErrEvictedMemory with the count of the remaining lines
+// if some lines have been evicted from memory.
+func (m *Document) countChunk(ctx context.Context, f io.ReaderAt, searcher Searcher, chunkNum int) (int, error) {
+	count, err := m.countChunkLines(ctx, f, searcher, chunkNum, ChunkSize)
+	if errors.Is(err, ErrEvictedMemory) {
+		return count, err
+	}
+	return count, ctx.Err()
+}
+
+// countChunkLines returns the number of matching lines in the chunk up to lineNum(exclusive).
+// The lines of the non-seekable document that have been evicted from memory cannot be counted,
+// and ErrEvictedMemory is returned with the count of the remaining lines.
+func (m *Document) countChunkLines(ctx context.Context, f io.ReaderAt, searcher Searcher, chunkNum int, lineNum int) (int, error) {
+	count := 0
+	if f != nil {
+		err := m.scanChunkFile(ctx, f, chunkNum, func(n int, line []byte) bool {
+			if n >= lineNum {
+				return false
+			}
+			if searcher.Match(line) != m.nonMatch {
+				count++
+			}
+			return true
+		})
+		return count, err
+	}
+
+	var evicted error
+	_, end := m.store.chunkRange(chunkNum)
+	for n := 0; n < min(end, lineNum); n++ {
+		line, err := m.store.GetChunkLine(chunkNum, n)
+		if err != nil {
+			evicted = fmt.Errorf("chunk %d %w", chunkNum, ErrEvictedMemory)
+			continue
+		}
+		if searcher.Match(line) != m.nonMatch {
+			count++
+		}
+		select {
+		case <-ctx.Done():
+			return count, ErrCancel
+		default:
+		}
+	}
+	return count, evicted
+}
+
+// setMatchCurrent sets the current match to lineNum.
+// The index in the chunk is counted in the background,
+// and the count of the previous current match is cancelled.
+func (m *Document) setMatchCurrent(searcher Searcher, lineNum int) {
+	mc := m.matchCount.Load()
+	if mc == nil || searcher == nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	mc.mu.Lock()
+	if mc.currentCancel != nil {
+		mc.currentCancel()
+	}
+	mc.currentCancel = cancel
+	mc.currentChunk = -1
+	mc.currentGen++
+	gen := mc.currentGen
+	mc.mu.Unlock()
+
+	f := m.countFile()
+	chunkNum, n := chunkLineNum(lineNum)
+	go func() {
+		defer cancel()
+		count, err := m.countChunkLines(ctx, f, searcher, chunkNum, n+1)
+		if err != nil || ctx.Err() != nil {
+			return
+		}
+		mc.mu.Lock()
+		defer mc.mu.Unlock()
+		if gen != mc.currentGen {
+			return
+		}
+		mc.currentChunk = chunkNum
+		mc.currentIndex = count
+		atomic.StoreInt32(&m.store.changed, 1)
+	}()
+}
+
+// setCount sets the count of the chunk.
+// partial is true if the chunk has been counted with lines evicted from memory.
+func (mc *matchCounter) setCount(chunkNum int, count int, partial bool, last bool, endNum int) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	for len(mc.counts) <= chunkNum {
+		mc.counts = append(mc.counts, 0)
+		mc.partials = append(mc.partials, false)
+	}
+	mc.counts[chunkNum] = count
+	mc.partials[chunkNum] = partial
+	if last {
+		mc.lines = endNum
+		mc.done = true
+		return
+	}
+	mc.counted = chunkNum + 1
+	mc.done = false
+}
+
+// isCounted returns true if endNum lines have been counted.
+func (mc *matchCounter) isCounted(endNum int) bool {
+	mc.mu.RLock()
+	defer mc.mu.RUnlock()
+	return mc.done && mc.lines == endNum
+}
+
+// String returns the status of the count.
+// e.g. "match 17 of 342". "..." is added while counting,
+// and "?" is displayed if the index of the current match is unknown.
+// " (partial)" is added if lines evicted from memory could not be counted.
+func (mc *matchCounter) String() string {
+	mc.mu.RLock()
+	defer mc.mu.RUnlock()
+	total := 0
+	for _, c := range mc.counts {
+		total += c
+	}
+	more := ""
+	if !mc.done {
+		more = "..."
+	}
+	partial := -1
+	for i, p := range mc.partials {
+		if p {
+			partial = i
+			break
+		}
+	}
+	if partial >= 0 {
+		more += " (partial)"
+	}
+
+	index := "?"
+	if mc.currentChunk >= 0 && (mc.currentChunk < mc.counted || mc.done) && (partial < 0 || mc.currentChunk <= partial) {
+		n := mc.currentIndex
+		for _, c := range mc.counts[:min(mc.currentChunk, len(mc.counts))] {
+			n += c
+		}
+		index = fmt.Sprintf("%d", n)
+	}
+	return fmt.Sprintf("match %s of %d%s", index, total, more)
+}
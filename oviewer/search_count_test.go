@@ -0,0 +1,230 @@
+package oviewer
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// waitMatchCount waits for the count to finish.
+func waitMatchCount(t *testing.T, mc *matchCounter) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		mc.mu.RLock()
+		done := mc.done && mc.currentChunk >= 0
+		mc.mu.RUnlock()
+		if done {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("timeout waiting for the match count")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestDocument_startMatchCount(t *testing.T) {
+	t.Parallel()
+	fileName := parallelSearchFile(t, 100000)
+	tests := []struct {
+		name    string
+		open    func(t *testing.T) *Document
+		word    string
+		lineNum int
+		want    string
+	}{
+		{
+			name: "testSeekable",
+			open: func(t *testing.T) *Document {
+				return openEOF(t, fileName)
+			},
+			word:    "line 9",
+			lineNum: 90000,
+			want:    "match 1112 of 11111",
+		},
+		{
+			name: "testReader",
+			open: func(t *testing.T) *Document {
+				f, err := os.Open(fileName)
+				if err != nil {
+					t.Fatal(err)
+				}
+				t.Cleanup(func() { f.Close() })
+				m, err := NewDocument()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := m.ControlReader(f, nil); err != nil {
+					t.Fatal(err)
+				}
+				for !m.BufEOF() {
+				}
+				return m
+			},
+			word:    "line 1234",
+			lineNum: 12345,
+			want:    "match 7 of 11",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			m := tt.open(t)
+			searcher := NewSearcher(tt.word, nil, false, false)
+			m.startMatchCount(searcher)
+			defer m.stopMatchCount()
+			mc := m.matchCount.Load()
+			m.setMatchCurrent(searcher, tt.lineNum)
+			waitMatchCount(t, mc)
+			if got := mc.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+
+			// The same searcher continues the count.
+			m.startMatchCount(NewSearcher(tt.word, nil, false, false))
+			if m.matchCount.Load() != mc {
+				t.Error("the count restarted with the same searcher")
+			}
+		})
+	}
+}
+
+func TestDocument_stopMatchCount(t *testing.T) {
+	t.Parallel()
+	m := openEOF(t, parallelSearchFile(t, 100))
+	m.startMatchCount(NewSearcher("line 1", nil, false, false))
+	first := m.matchCount.Load()
+	m.startMatchCount(NewSearcher("line 2", nil, false, false))
+	if m.matchCount.Load() == first {
+		t.Fatal("the count did not restart with a new searcher")
+	}
+	m.startMatchCount(nil)
+	if m.matchCount.Load() != nil {
+		t.Error("the count did not stop")
+	}
+}
+
+func TestDocument_matchCountEvicted(t *testing.T) {
+	t.Parallel()
+	f, err := os.Open(parallelSearchFile(t, 30000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { f.Close() })
+	m, err := NewDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.ControlReader(f, nil); err != nil {
+		t.Fatal(err)
+	}
+	for !m.BufEOF() {
+	}
+	m.store.mu.Lock()
+	m.store.unloadChunk(1)
+	m.store.mu.Unlock()
+
+	searcher := NewSearcher("line 1234", nil, false, false)
+	m.startMatchCount(searcher)
+	defer m.stopMatchCount()
+	mc := m.matchCount.Load()
+	m.setMatchCurrent(searcher, 1234)
+	waitMatchCount(t, mc)
+	if got, want := mc.String(), "match 1 of 1 (partial)"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestRoot_setDocumentStopMatchCount(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root, err := NewRoot(bytes.NewBufferString("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := root.Doc
+	m.startMatchCount(NewSearcher("test", nil, false, false))
+	doc, err := NewDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	root.setDocument(doc)
+	if m.matchCount.Load() != nil {
+		t.Error("the count of the previous document did not stop")
+	}
+}
+
+func Test_matchCounter_String(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		mc   *matchCounter
+		want string
+	}{
+		{
+			name: "testCounting",
+			mc:   &matchCounter{counts: []int{3, 4}, counted: 1, currentChunk: 1, currentIndex: 2},
+			want: "match ? of 7...",
+		},
+		{
+			name: "testCountingIndex",
+			mc:   &matchCounter{counts: []int{3, 4}, counted: 1, currentChunk: 0, currentIndex: 2},
+			want: "match 2 of 7...",
+		},
+		{
+			name: "testDone",
+			mc:   &matchCounter{counts: []int{3, 4}, counted: 1, done: true, currentChunk: 1, currentIndex: 2},
+			want: "match 5 of 7",
+		},
+		{
+			name: "testNoCurrent",
+			mc:   &matchCounter{counts: []int{3}, done: true, currentChunk: -1},
+			want: "match ? of 3",
+		},
+		{
+			name: "testPartial",
+			mc:   &matchCounter{counts: []int{3, 4}, partials: []bool{false, true}, counted: 1, done: true, currentChunk: 1, currentIndex: 2},
+			want: "match 5 of 7 (partial)",
+		},
+		{
+			name: "testPartialBefore",
+			mc:   &matchCounter{counts: []int{3, 4}, partials: []bool{true, false}, counted: 1, done: true, currentChunk: 1, currentIndex: 2},
+			want: "match ? of 7 (partial)",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.mc.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocument_setMatchCurrentLatest(t *testing.T) {
+	t.Parallel()
+	m := openEOF(t, parallelSearchFile(t, 100000))
+	searcher := NewSearcher("line 1234", nil, false, false)
+	m.startMatchCount(searcher)
+	defer m.stopMatchCount()
+	mc := m.matchCount.Load()
+	// Only the last current match is reflected.
+	for i := 0; i < 10; i++ {
+		m.setMatchCurrent(searcher, 12349)
+	}
+	m.setMatchCurrent(searcher, 1234)
+	waitMatchCount(t, mc)
+	time.Sleep(50 * time.Millisecond)
+	if got, want := mc.String(), "match 1 of 11"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
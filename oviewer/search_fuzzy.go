@@ -0,0 +1,221 @@
+package oviewer
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// Scores of the fuzzy search.
+const (
+	// fuzzyScoreMatch is the score of a matched character.
+	fuzzyScoreMatch = 16
+	// fuzzyBonusConsecutive is the bonus of a character that follows the previous matched character.
+	fuzzyBonusConsecutive = 8
+	// fuzzyBonusBoundary is the bonus of a character at the beginning of a word.
+	fuzzyBonusBoundary = 8
+	// fuzzyPenaltyGap is the penalty of a character skipped between matched characters.
+	fuzzyPenaltyGap = 1
+	// fuzzyMinScore is the minimum score per character of the pattern for a line to match.
+	// The characters scattered over the line do not match.
+	fuzzyMinScore = fuzzyScoreMatch / 2
+	// fuzzyMaxCandidates is the maximum number of start candidates scored in a line.
+	fuzzyMaxCandidates = 16
+)
+
+// fuzzyWord is a fuzzy search.
+// The line matches if it contains the characters of the word in order
+// and the score is at least fuzzyMinScore per character.
+type fuzzyWord struct {
+	word          string
+	pattern       []rune
+	caseSensitive bool
+}
+
+// NewFuzzySearcher returns the Searcher interface for the fuzzy search.
+// The line matches if it contains the characters of the word in order with enough score,
+// and the best scoring characters are highlighted.
+func NewFuzzySearcher(word string, caseSensitive bool) Searcher {
+	pattern := []rune(word)
+	if !caseSensitive {
+		for i, r := range pattern {
+			pattern[i] = unicode.ToLower(r)
+		}
+	}
+	return fuzzyWord{
+		word:          word,
+		pattern:       pattern,
+		caseSensitive: caseSensitive,
+	}
+}
+
+// fuzzyWord Match is a fuzzy search for bytes.
+func (substr fuzzyWord) Match(s []byte) bool {
+	s = stripEscapeSequenceBytes(s)
+	p := 0
+	for b := s; len(b) > 0 && p < len(substr.pattern); {
+		r, size := utf8.DecodeRune(b)
+		if substr.equal(r, substr.pattern[p]) {
+			p++
+		}
+		b = b[size:]
+	}
+	if p < len(substr.pattern) {
+		return false
+	}
+	score, _ := substr.score(string(s))
+	return score >= substr.minScore()
+}
+
+// fuzzyWord MatchString is a fuzzy search for string.
+func (substr fuzzyWord) MatchString(s string) bool {
+	s = stripEscapeSequenceString(s)
+	p := 0
+	for _, r := range s {
+		if p == len(substr.pattern) {
+			break
+		}
+		if substr.equal(r, substr.pattern[p]) {
+			p++
+		}
+	}
+	if p < len(substr.pattern) {
+		return false
+	}
+	score, _ := substr.score(s)
+	return score >= substr.minScore()
+}
+
+// fuzzyWord FindAll returns the index of the matched characters of the best score.
+// The consecutive characters are combined into one index.
+func (substr fuzzyWord) FindAll(s string) [][]int {
+	score, positions := substr.score(s)
+	if score < substr.minScore() {
+		return nil
+	}
+	var indexes [][]int
+	for _, pos := range positions {
+		_, size := utf8.DecodeRuneInString(s[pos:])
+		if n := len(indexes); n > 0 && indexes[n-1][1] == pos {
+			indexes[n-1][1] = pos + size
+			continue
+		}
+		indexes = append(indexes, []int{pos, pos + size})
+	}
+	return indexes
+}
+
+// fuzzyWord String returns the search word.
+func (substr fuzzyWord) String() string {
+	return substr.word
+}
+
+// minScore returns the minimum score for a line to match.
+func (substr fuzzyWord) minScore() int {
+	return len(substr.pattern) * fuzzyMinScore
+}
+
+// equal returns true if the character of the line is equal to the character of the pattern.
+func (substr fuzzyWord) equal(r rune, p rune) bool {
+	if !substr.caseSensitive {
+		r = unicode.ToLower(r)
+	}
+	return r == p
+}
+
+// score returns the best score and the byte positions of the matched characters.
+// It returns 0 and nil if the line does not match.
+//
+// The characters are matched forward from a start candidate,
+// and then backward from the end to find the shortest range.
+// Each shortest range is scored, and the best one is returned.
+// Up to fuzzyMaxCandidates ranges are scored, so the work is linear in the length of the line.
+func (substr fuzzyWord) score(s string) (int, []int) {
+	if len(substr.pattern) == 0 {
+		return 0, nil
+	}
+	runes := make([]rune, 0, len(s))
+	offsets := make([]int, 0, len(s))
+	for i, r := range s {
+		runes = append(runes, r)
+		offsets = append(offsets, i)
+	}
+
+	best, bestMatched := 0, []int(nil)
+	matched := make([]int, len(substr.pattern))
+	for start, n := 0, 0; start < len(runes) && n < fuzzyMaxCandidates; n++ {
+		// Match forward.
+		end, p := -1, 0
+		for i := start; i < len(runes); i++ {
+			if substr.equal(runes[i], substr.pattern[p]) {
+				p++
+				if p == len(substr.pattern) {
+					end = i
+					break
+				}
+			}
+		}
+		if end < 0 {
+			break
+		}
+
+		// Match backward to find the shortest range.
+		p = len(substr.pattern) - 1
+		for i := end; i >= start; i-- {
+			if substr.equal(runes[i], substr.pattern[p]) {
+				matched[p] = i
+				if p == 0 {
+					break
+				}
+				p--
+			}
+		}
+
+		start = matched[0] + 1
+		if score := fuzzyScore(runes, matched); score > best {
+			best = score
+			bestMatched, matched = matched, bestMatched
+			if matched == nil {
+				matched = make([]int, len(substr.pattern))
+			}
+		}
+	}
+	if bestMatched == nil {
+		return 0, nil
+	}
+	positions := make([]int, len(bestMatched))
+	for i, m := range bestMatched {
+		positions[i] = offsets[m]
+	}
+	return best, positions
+}
+
+// fuzzyScore returns the score of the matched characters.
+func fuzzyScore(runes []rune, matched []int) int {
+	score := 0
+	for i, m := range matched {
+		score += fuzzyScoreMatch
+		if i > 0 {
+			if gap := m - matched[i-1] - 1; gap == 0 {
+				score += fuzzyBonusConsecutive
+			} else {
+				score -= gap * fuzzyPenaltyGap
+			}
+		}
+		if isWordBoundary(runes, m) {
+			score += fuzzyBonusBoundary
+		}
+	}
+	return max(score, 1)
+}
+
+// isWordBoundary returns true if the character at i is the beginning of a word.
+func isWordBoundary(runes []rune, i int) bool {
+	if i == 0 {
+		return true
+	}
+	prev, cur := runes[i-1], runes[i]
+	if !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+		return unicode.IsLetter(cur) || unicode.IsDigit(cur)
+	}
+	return unicode.IsLower(prev) && unicode.IsUpper(cur)
+}
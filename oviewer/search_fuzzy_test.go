@@ -0,0 +1,132 @@
+package oviewer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_fuzzyWord_Match(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		word          string
+		caseSensitive bool
+		s             string
+		want          bool
+	}{
+		{
+			name: "testSubsequence",
+			word: "kbapi",
+			s:    "kube-system   kube-apiserver-master   1/1   Running",
+			want: true,
+		},
+		{
+			name: "testOrder",
+			word: "ipa",
+			s:    "kube-apiserver",
+			want: false,
+		},
+		{
+			name: "testIgnoreCase",
+			word: "kapi",
+			s:    "Kube-APIserver",
+			want: true,
+		},
+		{
+			name:          "testCaseSensitive",
+			word:          "kapi",
+			caseSensitive: true,
+			s:             "Kube-APIserver",
+			want:          false,
+		},
+		{
+			name: "testEscapeSequence",
+			word: "ab",
+			s:    "\x1b[31ma\x1b[mb",
+			want: true,
+		},
+		{
+			name: "testMultiByte",
+			word: "あう",
+			s:    "あいう",
+			want: true,
+		},
+		{
+			name: "testScattered",
+			word: "kapi",
+			s:    "k" + strings.Repeat(" ", 40) + "a" + strings.Repeat(" ", 40) + "p" + strings.Repeat(" ", 40) + "i",
+			want: false,
+		},
+		{
+			name: "testLongLine",
+			word: "aab",
+			s:    strings.Repeat("a", 100000) + "b",
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			searcher := NewFuzzySearcher(tt.word, tt.caseSensitive)
+			if got := searcher.Match([]byte(tt.s)); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+			if got := searcher.MatchString(tt.s); got != tt.want {
+				t.Errorf("MatchString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_fuzzyWord_FindAll(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		word string
+		s    string
+		want [][]int
+	}{
+		{
+			name: "testConsecutive",
+			word: "api",
+			s:    "a-p-i kube-apiserver",
+			want: [][]int{{11, 14}},
+		},
+		{
+			name: "testBoundary",
+			word: "kas",
+			s:    "kube-apiserver",
+			want: [][]int{{0, 1}, {5, 6}, {8, 9}},
+		},
+		{
+			name: "testMultiByte",
+			word: "あう",
+			s:    "あいう",
+			want: [][]int{{0, 3}, {6, 9}},
+		},
+		{
+			name: "testNotMatch",
+			word: "xyz",
+			s:    "kube-apiserver",
+			want: nil,
+		},
+		{
+			name: "testScattered",
+			word: "ki",
+			s:    "kube" + strings.Repeat("-", 40) + "apiserver",
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			searcher := NewFuzzySearcher(tt.word, false)
+			if got := searcher.FindAll(tt.s); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindAll() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
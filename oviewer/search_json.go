@@ -0,0 +1,547 @@
+package oviewer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// jsonWord is a search that evaluates the expression against the fields of JSON lines.
+// e.g. `.level == "error" && .latency_ms > 500`.
+type jsonWord struct {
+	word string
+	expr jsonExpr
+}
+
+// NewJSONSearcher returns the Searcher interface that matches the JSON lines
+// for which the expression is true.
+//
+// The expression consists of the following:
+//
+//	.field .field.child .array[0] ."field name"  field values
+//	"string" 123 1.5 true false null             literals
+//	== != < <= > >=                              comparisons
+//	=~                                           regular expression match
+//	&& || ! ( )                                  logical operators
+//
+// A field alone is true if it exists and is not false or null.
+// The lines that are not JSON objects do not match.
+func NewJSONSearcher(expr string) (Searcher, error) {
+	p := &jsonParser{tokens: nil}
+	if err := p.tokenize(expr); err != nil {
+		return nil, err
+	}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("%w: unexpected %s", ErrInvalidJSONExpr, p.tokens[p.pos].str)
+	}
+	return jsonWord{
+		word: expr,
+		expr: e,
+	}, nil
+}
+
+// jsonWord Match evaluates the expression for bytes.
+func (substr jsonWord) Match(s []byte) bool {
+	v, ok := jsonLineValue(stripEscapeSequenceBytes(s))
+	if !ok {
+		return false
+	}
+	return jsonTruthy(substr.expr.eval(v))
+}
+
+// jsonWord MatchString evaluates the expression for string.
+func (substr jsonWord) MatchString(s string) bool {
+	return substr.Match([]byte(s))
+}
+
+// jsonWord FindAll returns nil because the expression does not match the position.
+func (substr jsonWord) FindAll(_ string) [][]int {
+	return nil
+}
+
+// jsonWord String returns the expression.
+func (substr jsonWord) String() string {
+	return substr.word
+}
+
+// jsonLineValue returns the value of the JSON object in the line.
+// The prefix before the object such as a timestamp is ignored.
+func jsonLineValue(line []byte) (any, bool) {
+	i := bytes.IndexByte(line, '{')
+	if i < 0 {
+		return nil, false
+	}
+	var v any
+	if err := json.Unmarshal(line[i:], &v); err != nil {
+		return nil, false
+	}
+	return v, true
+}
+
+// jsonTruthy returns true if the value is not nil or false.
+func jsonTruthy(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	}
+	return true
+}
+
+// jsonExpr is a node of the expression.
+type jsonExpr interface {
+	eval(v any) any
+}
+
+// jsonPath is the value of the field.
+type jsonPath struct {
+	keys []any
+}
+
+func (e jsonPath) eval(v any) any {
+	for _, key := range e.keys {
+		switch key := key.(type) {
+		case string:
+			obj, ok := v.(map[string]any)
+			if !ok {
+				return nil
+			}
+			v = obj[key]
+		case int:
+			array, ok := v.([]any)
+			if !ok || key >= len(array) {
+				return nil
+			}
+			v = array[key]
+		}
+	}
+	return v
+}
+
+// jsonLiteral is a literal value.
+type jsonLiteral struct {
+	value any
+}
+
+func (e jsonLiteral) eval(_ any) any {
+	return e.value
+}
+
+// jsonNot is the negation of the expression.
+type jsonNot struct {
+	x jsonExpr
+}
+
+func (e jsonNot) eval(v any) any {
+	return !jsonTruthy(e.x.eval(v))
+}
+
+// jsonLogical is && or ||.
+type jsonLogical struct {
+	op          string
+	left, right jsonExpr
+}
+
+func (e jsonLogical) eval(v any) any {
+	l := jsonTruthy(e.left.eval(v))
+	if e.op == "&&" {
+		return l && jsonTruthy(e.right.eval(v))
+	}
+	return l || jsonTruthy(e.right.eval(v))
+}
+
+// jsonCompare is a comparison.
+type jsonCompare struct {
+	op          string
+	left, right jsonExpr
+	re          *regexp.Regexp
+}
+
+func (e jsonCompare) eval(v any) any {
+	l := e.left.eval(v)
+	if e.op == "=~" {
+		s, ok := l.(string)
+		return ok && e.re.MatchString(s)
+	}
+	r := e.right.eval(v)
+	switch e.op {
+	case "==":
+		return jsonEqual(l, r)
+	case "!=":
+		return !jsonEqual(l, r)
+	}
+
+	var c int
+	switch l := l.(type) {
+	case float64:
+		r, ok := r.(float64)
+		if !ok {
+			return false
+		}
+		switch {
+		case l < r:
+			c = -1
+		case l > r:
+			c = 1
+		}
+	case string:
+		r, ok := r.(string)
+		if !ok {
+			return false
+		}
+		c = strings.Compare(l, r)
+	default:
+		return false
+	}
+	switch e.op {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	return false
+}
+
+// jsonEqual returns true if the values are equal.
+// Objects and arrays are not equal to anything.
+func jsonEqual(l any, r any) bool {
+	switch l.(type) {
+	case nil, bool, float64, string:
+		return l == r
+	}
+	return false
+}
+
+// jsonToken is a token of the expression.
+type jsonToken struct {
+	kind int
+	str  string
+}
+
+// The kinds of jsonToken.
+const (
+	jsonTokenOp = iota
+	jsonTokenPath
+	jsonTokenString
+	jsonTokenNumber
+	jsonTokenWord
+)
+
+// jsonParser is a recursive descent parser of the expression.
+type jsonParser struct {
+	tokens []jsonToken
+	pos    int
+}
+
+// jsonOperators is the operators in order of matching.
+var jsonOperators = []string{"==", "!=", "<=", ">=", "=~", "&&", "||", "<", ">", "!", "(", ")"}
+
+// tokenize splits the expression into tokens.
+func (p *jsonParser) tokenize(expr string) error {
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		r, _ := utf8.DecodeRuneInString(expr[i:])
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+			continue
+		case c == '.':
+			n, err := jsonPathLen(expr[i:])
+			if err != nil {
+				return err
+			}
+			p.tokens = append(p.tokens, jsonToken{kind: jsonTokenPath, str: expr[i : i+n]})
+			i += n
+			continue
+		case c == '"':
+			n, err := jsonStringLen(expr[i:])
+			if err != nil {
+				return err
+			}
+			p.tokens = append(p.tokens, jsonToken{kind: jsonTokenString, str: expr[i : i+n]})
+			i += n
+			continue
+		case c == '-' || (c >= '0' && c <= '9'):
+			n := 1
+			for i+n < len(expr) && strings.IndexByte("0123456789.eE+-", expr[i+n]) >= 0 {
+				n++
+			}
+			p.tokens = append(p.tokens, jsonToken{kind: jsonTokenNumber, str: expr[i : i+n]})
+			i += n
+			continue
+		case unicode.IsLetter(r):
+			n := jsonWordLen(expr[i:])
+			p.tokens = append(p.tokens, jsonToken{kind: jsonTokenWord, str: expr[i : i+n]})
+			i += n
+			continue
+		}
+
+		found := false
+		for _, op := range jsonOperators {
+			if strings.HasPrefix(expr[i:], op) {
+				p.tokens = append(p.tokens, jsonToken{kind: jsonTokenOp, str: op})
+				i += len(op)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%w: unexpected %q", ErrInvalidJSONExpr, r)
+		}
+	}
+	return nil
+}
+
+// jsonWordLen returns the length in bytes of the letters at the beginning of s.
+func jsonWordLen(s string) int {
+	n := 0
+	for n < len(s) {
+		r, size := utf8.DecodeRuneInString(s[n:])
+		if !unicode.IsLetter(r) {
+			break
+		}
+		n += size
+	}
+	return n
+}
+
+// jsonPathLen returns the length of the path at the beginning of s.
+func jsonPathLen(s string) (int, error) {
+	i := 0
+	for i < len(s) {
+		switch s[i] {
+		case '.':
+			i++
+			if i < len(s) && s[i] == '"' {
+				n, err := jsonStringLen(s[i:])
+				if err != nil {
+					return 0, err
+				}
+				i += n
+				continue
+			}
+			for i < len(s) && isJSONIdent(s[i]) {
+				i++
+			}
+		case '[':
+			end := strings.IndexByte(s[i:], ']')
+			if end < 0 {
+				return 0, fmt.Errorf("%w: missing ]", ErrInvalidJSONExpr)
+			}
+			i += end + 1
+		default:
+			return i, nil
+		}
+	}
+	return i, nil
+}
+
+// jsonStringLen returns the length of the quoted string at the beginning of s.
+func jsonStringLen(s string) (int, error) {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: unterminated string", ErrInvalidJSONExpr)
+}
+
+// isJSONIdent returns true if c can be used in the field name without quotes.
+func isJSONIdent(c byte) bool {
+	return c == '_' || c == '-' || c == '@' ||
+		('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+// peek returns the current operator, or "" if it is not an operator.
+func (p *jsonParser) peek() string {
+	if p.pos < len(p.tokens) && p.tokens[p.pos].kind == jsonTokenOp {
+		return p.tokens[p.pos].str
+	}
+	return ""
+}
+
+// parseOr parses the || expression.
+func (p *jsonParser) parseOr() (jsonExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "||" {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = jsonLogical{op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseAnd parses the && expression.
+func (p *jsonParser) parseAnd() (jsonExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "&&" {
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = jsonLogical{op: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseUnary parses the ! expression.
+func (p *jsonParser) parseUnary() (jsonExpr, error) {
+	if p.peek() == "!" {
+		p.pos++
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return jsonNot{x: x}, nil
+	}
+	return p.parseCompare()
+}
+
+// parseCompare parses the comparison.
+func (p *jsonParser) parseCompare() (jsonExpr, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	op := p.peek()
+	switch op {
+	case "==", "!=", "<", "<=", ">", ">=":
+	case "=~":
+		p.pos++
+		if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != jsonTokenString {
+			return nil, fmt.Errorf("%w: =~ requires a string", ErrInvalidJSONExpr)
+		}
+		str, err := strconv.Unquote(p.tokens[p.pos].str)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidJSONExpr, err)
+		}
+		re, err := regexp.Compile(str)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidJSONExpr, err)
+		}
+		p.pos++
+		return jsonCompare{op: op, left: left, re: re}, nil
+	default:
+		return left, nil
+	}
+	p.pos++
+	right, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	return jsonCompare{op: op, left: left, right: right}, nil
+}
+
+// parsePrimary parses the field, the literal and the parenthesized expression.
+func (p *jsonParser) parsePrimary() (jsonExpr, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("%w: unexpected end", ErrInvalidJSONExpr)
+	}
+	t := p.tokens[p.pos]
+	p.pos++
+	switch t.kind {
+	case jsonTokenPath:
+		return parseJSONPath(t.str)
+	case jsonTokenString:
+		str, err := strconv.Unquote(t.str)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidJSONExpr, err)
+		}
+		return jsonLiteral{value: str}, nil
+	case jsonTokenNumber:
+		f, err := strconv.ParseFloat(t.str, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidJSONExpr, err)
+		}
+		return jsonLiteral{value: f}, nil
+	case jsonTokenWord:
+		switch t.str {
+		case "true":
+			return jsonLiteral{value: true}, nil
+		case "false":
+			return jsonLiteral{value: false}, nil
+		case "null":
+			return jsonLiteral{value: nil}, nil
+		}
+	case jsonTokenOp:
+		if t.str == "(" {
+			x, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if p.peek() != ")" {
+				return nil, fmt.Errorf("%w: missing )", ErrInvalidJSONExpr)
+			}
+			p.pos++
+			return x, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: unexpected %s", ErrInvalidJSONExpr, t.str)
+}
+
+// parseJSONPath parses the path such as .a.b[0]."c d".
+func parseJSONPath(s string) (jsonExpr, error) {
+	var keys []any
+	for i := 0; i < len(s); {
+		switch s[i] {
+		case '.':
+			i++
+			if i < len(s) && s[i] == '"' {
+				n, err := jsonStringLen(s[i:])
+				if err != nil {
+					return nil, err
+				}
+				key, err := strconv.Unquote(s[i : i+n])
+				if err != nil {
+					return nil, fmt.Errorf("%w: %s", ErrInvalidJSONExpr, err)
+				}
+				keys = append(keys, key)
+				i += n
+				continue
+			}
+			start := i
+			for i < len(s) && isJSONIdent(s[i]) {
+				i++
+			}
+			if start < i {
+				keys = append(keys, s[start:i])
+			}
+		case '[':
+			end := i + strings.IndexByte(s[i:], ']')
+			n, err := strconv.Atoi(s[i+1 : end])
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("%w: invalid index %s", ErrInvalidJSONExpr, s[i:end+1])
+			}
+			keys = append(keys, n)
+			i = end + 1
+		}
+	}
+	return jsonPath{keys: keys}, nil
+}
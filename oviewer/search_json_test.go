@@ -0,0 +1,120 @@
+package oviewer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNewJSONSearcher(t *testing.T) {
+	t.Parallel()
+	lines := []string{
+		`{"level":"error","latency_ms":800,"user":{"name":"alice"},"tags":["a","b"]}`,
+		`{"level":"error","latency_ms":100}`,
+		`{"level":"info","latency_ms":900,"user":{"name":"bob"},"ok":true}`,
+		`2023-01-01T00:00:00Z {"level":"warn","msg":"timeout 30s","user name":"carol"}`,
+		`not json`,
+		"\x1b[32m{\"level\":\"error\",\"latency_ms\":600}\x1b[0m",
+	}
+	tests := []struct {
+		name string
+		expr string
+		want []bool
+	}{
+		{
+			name: "testAnd",
+			expr: `.level == "error" && .latency_ms > 500`,
+			want: []bool{true, false, false, false, false, true},
+		},
+		{
+			name: "testOr",
+			expr: `.level == "info" || .level == "warn"`,
+			want: []bool{false, false, true, true, false, false},
+		},
+		{
+			name: "testNot",
+			expr: `!(.level == "error")`,
+			want: []bool{false, false, true, true, false, false},
+		},
+		{
+			name: "testNested",
+			expr: `.user.name != "bob" && .user.name`,
+			want: []bool{true, false, false, false, false, false},
+		},
+		{
+			name: "testIndex",
+			expr: `.tags[1] == "b"`,
+			want: []bool{true, false, false, false, false, false},
+		},
+		{
+			name: "testRegexp",
+			expr: `.msg =~ "^time"`,
+			want: []bool{false, false, false, true, false, false},
+		},
+		{
+			name: "testQuoted",
+			expr: `."user name" >= "c"`,
+			want: []bool{false, false, false, true, false, false},
+		},
+		{
+			name: "testBool",
+			expr: `.ok == true`,
+			want: []bool{false, false, true, false, false, false},
+		},
+		{
+			name: "testNull",
+			expr: `.user == null`,
+			want: []bool{false, true, false, true, false, true},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			searcher, err := NewJSONSearcher(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, line := range lines {
+				if got := searcher.Match([]byte(line)); got != tt.want[i] {
+					t.Errorf("Match(%q) = %v, want %v", line, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNewJSONSearcher_error(t *testing.T) {
+	t.Parallel()
+	for _, expr := range []string{
+		`.level ==`,
+		`.level == "error`,
+		`(.level == "error"`,
+		`.level = "error"`,
+		`.msg =~ 1`,
+		`.msg =~ "("`,
+		`.a[x] == 1`,
+		`foo`,
+	} {
+		if _, err := NewJSONSearcher(expr); !errors.Is(err, ErrInvalidJSONExpr) {
+			t.Errorf("NewJSONSearcher(%q) error = %v, want %v", expr, err, ErrInvalidJSONExpr)
+		}
+	}
+}
+
+func TestNewJSONSearcher_errorRune(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{expr: `.a == →`, want: `'→'`},
+		{expr: `.a == ñull`, want: `ñull`},
+	}
+	for _, tt := range tests {
+		_, err := NewJSONSearcher(tt.expr)
+		if !errors.Is(err, ErrInvalidJSONExpr) || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("NewJSONSearcher(%q) error = %v, want %s", tt.expr, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package oviewer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"runtime"
+	"sync/atomic"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// parallelSearchChunks is the number of chunks of the seekable file searched in parallel.
+// The chunks are read from the open file with ReadAt, which is safe for concurrent use.
+var parallelSearchChunks = min(runtime.NumCPU(), 16)
+
+// canSearchParallel returns true if the document can be searched in parallel.
+func (m *Document) canSearchParallel() bool {
+	if parallelSearchChunks < 2 || !m.seekable || m.file == nil || m.checkClose() {
+		return false
+	}
+	return atomic.LoadInt32(&m.tmpFollow) == 0
+}
+
+// parallelSearch searches the chunks from chunkNum in the search direction in parallel,
+// and returns the first matching line in the search direction.
+func (m *Document) parallelSearch(ctx context.Context, searcher Searcher, chunkNum int, forward bool) (int, error) {
+	// The file is not reopened by name, because it may have been renamed or rotated.
+	f := m.file
+	step := 1
+	if !forward {
+		step = -1
+	}
+	minChunk, _ := chunkLineNum(m.BufStartNum())
+	for cn := chunkNum; ; cn += step * parallelSearchChunks {
+		// lastChunkNum may be updated while searching.
+		lastChunk := m.store.lastChunkNum()
+		batch := make([]int, 0, parallelSearchChunks)
+		for c := cn; len(batch) < parallelSearchChunks && c >= minChunk && c <= lastChunk; c += step {
+			batch = append(batch, c)
+		}
+		if len(batch) == 0 {
+			break
+		}
+
+		i, n, err := m.searchChunksParallel(ctx, f, searcher, batch, forward)
+		if err != nil {
+			return 0, err
+		}
+		if i >= 0 {
+			return batch[i]*ChunkSize + n, nil
+		}
+	}
+	if forward {
+		return m.BufEndNum(), ErrNotFound
+	}
+	return 0, ErrNotFound
+}
+
+// searchChunksParallel searches the chunks of the batch in parallel.
+// It returns the index of the first chunk in the batch that matched and the line number in the chunk.
+// The chunks after the matched chunk stop searching.
+func (m *Document) searchChunksParallel(ctx context.Context, f io.ReaderAt, searcher Searcher, batch []int, forward bool) (int, int, error) {
+	found := int32(len(batch))
+	results := make([]int, len(batch))
+	eg, ctx := errgroup.WithContext(ctx)
+	for i, chunkNum := range batch {
+		i, chunkNum := i, chunkNum
+		eg.Go(func() error {
+			results[i] = -1
+			stop := func() bool {
+				return atomic.LoadInt32(&found) < int32(i)
+			}
+			n, err := m.searchChunkFile(ctx, f, searcher, chunkNum, forward, stop)
+			if err != nil {
+				if errors.Is(err, ErrNotFound) {
+					return nil
+				}
+				return err
+			}
+			results[i] = n
+			for {
+				old := atomic.LoadInt32(&found)
+				if int32(i) >= old || atomic.CompareAndSwapInt32(&found, old, int32(i)) {
+					break
+				}
+			}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return -1, 0, err
+	}
+	for i, n := range results {
+		if n >= 0 {
+			return i, n, nil
+		}
+	}
+	return -1, 0, nil
+}
+
+// searchChunkFile searches the chunk by reading f.
+// It returns the first (forward) or last (backward) matching line number in the chunk.
+// It returns ErrNotFound if stop returns true.
+func (m *Document) searchChunkFile(ctx context.Context, f io.ReaderAt, searcher Searcher, chunkNum int, forward bool, stop func() bool) (int, error) {
+	match := -1
+	err := m.scanChunkFile(ctx, f, chunkNum, func(n int, line []byte) bool {
+		if searcher.Match(line) != m.nonMatch {
+			match = n
+			if forward {
+				return false
+			}
+		}
+		return !stop()
+	})
+	if err != nil {
+		return 0, err
+	}
+	if match < 0 {
+		return 0, ErrNotFound
+	}
+	return match, nil
+}
+
+// scanChunkFile reads the lines of the chunk from f and calls fn for each line.
+// The line does not contain the newline. Scanning stops when fn returns false.
+func (m *Document) scanChunkFile(ctx context.Context, f io.ReaderAt, chunkNum int, fn func(n int, line []byte) bool) error {
+	start, size, lines := m.chunkSpan(chunkNum)
+	reader := bufio.NewReader(io.NewSectionReader(f, start, size))
+	var line bytes.Buffer
+	for n := 0; n < lines; n++ {
+		line.Reset()
+		var err error
+		for {
+			var buf []byte
+			buf, err = reader.ReadSlice('\n')
+			line.Write(buf)
+			if !errors.Is(err, bufio.ErrBufferFull) {
+				break
+			}
+		}
+		if line.Len() == 0 && err != nil {
+			break
+		}
+		if !fn(n, bytes.TrimSuffix(line.Bytes(), []byte("\n"))) {
+			break
+		}
+		if err != nil {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ErrCancel
+		default:
+		}
+	}
+	return nil
+}
+
+// chunkSpan returns the start offset, the size and the number of lines of the chunk.
+func (m *Document) chunkSpan(chunkNum int) (int64, int64, int) {
+	s := m.store
+	_, lines := s.chunkRange(chunkNum)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	start := s.chunks[chunkNum].start
+	size := int64(1<<63-1) - start
+	if chunkNum+1 < len(s.chunks) {
+		size = s.chunks[chunkNum+1].start - start
+	}
+	return start, size, lines
+}
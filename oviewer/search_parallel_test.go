@@ -0,0 +1,182 @@
+package oviewer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func parallelSearchFile(t *testing.T, n int) string {
+	t.Helper()
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "line %d\n", i)
+	}
+	fileName := filepath.Join(t.TempDir(), "search.txt")
+	if err := os.WriteFile(fileName, []byte(b.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return fileName
+}
+
+func TestDocument_parallelSearch(t *testing.T) {
+	fileName := parallelSearchFile(t, 100000)
+	tests := []struct {
+		name     string
+		word     string
+		forward  bool
+		nonMatch bool
+		lineNum  int
+		want     int
+		wantErr  error
+	}{
+		{
+			name:    "testForward",
+			word:    "line 87654",
+			forward: true,
+			lineNum: 0,
+			want:    87654,
+		},
+		{
+			name:    "testForwardFirst",
+			word:    "line 9",
+			forward: true,
+			lineNum: 10000,
+			want:    90000,
+		},
+		{
+			name:    "testBackward",
+			word:    "line 1234",
+			forward: false,
+			lineNum: 99999,
+			want:    12349,
+		},
+		{
+			name:     "testNonMatch",
+			word:     "line",
+			forward:  true,
+			nonMatch: true,
+			lineNum:  0,
+			wantErr:  ErrNotFound,
+		},
+		{
+			name:    "testNotFound",
+			word:    "notfound",
+			forward: true,
+			lineNum: 0,
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "testBackNotFound",
+			word:    "notfound",
+			forward: false,
+			lineNum: 99999,
+			wantErr: ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, chunks := range []int{1, 4} {
+				func() {
+					defer func(n int) {
+						parallelSearchChunks = n
+					}(parallelSearchChunks)
+					parallelSearchChunks = chunks
+
+					m := openEOF(t, fileName)
+					m.nonMatch = tt.nonMatch
+					searcher := NewSearcher(tt.word, nil, false, false)
+					got, err := m.searchLine(context.Background(), searcher, tt.forward, tt.lineNum)
+					if !errors.Is(err, tt.wantErr) {
+						t.Fatalf("searchLine(%d) error = %v, wantErr %v", chunks, err, tt.wantErr)
+					}
+					if err != nil {
+						return
+					}
+					if got != tt.want {
+						t.Errorf("searchLine(%d) = %v, want %v", chunks, got, tt.want)
+					}
+				}()
+			}
+		})
+	}
+}
+
+func TestDocument_parallelSearchCancel(t *testing.T) {
+	defer func(n int) {
+		parallelSearchChunks = n
+	}(parallelSearchChunks)
+	parallelSearchChunks = 4
+
+	m := openEOF(t, parallelSearchFile(t, 100000))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	searcher := NewSearcher("notfound", nil, false, false)
+	if _, err := m.SearchLine(ctx, searcher, 0); !errors.Is(err, ErrCancel) {
+		t.Errorf("SearchLine() error = %v, want %v", err, ErrCancel)
+	}
+}
+
+func TestDocument_parallelSearchRotated(t *testing.T) {
+	defer func(n int) {
+		parallelSearchChunks = n
+	}(parallelSearchChunks)
+	parallelSearchChunks = 4
+
+	fileName := parallelSearchFile(t, 100000)
+	m := openEOF(t, fileName)
+	// The file is rotated, and a new file is created with the same name.
+	if err := os.Rename(fileName, fileName+".1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(fileName, []byte("rotated\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	searcher := NewSearcher("line 98765", nil, false, false)
+	got, err := m.SearchLine(context.Background(), searcher, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 98765 {
+		t.Errorf("SearchLine() = %d, want %d", got, 98765)
+	}
+}
+
+func TestDocument_searchWriterEvicted(t *testing.T) {
+	defer func(n int) {
+		parallelSearchChunks = n
+	}(parallelSearchChunks)
+	parallelSearchChunks = 4
+
+	m := openEOF(t, parallelSearchFile(t, 30000))
+	// Evict the chunks so that the lines found in the file are not in memory.
+	m.store.mu.Lock()
+	for chunkNum := 1; chunkNum < len(m.store.chunks); chunkNum++ {
+		m.store.unloadChunk(chunkNum)
+	}
+	m.store.mu.Unlock()
+
+	r, w := io.Pipe()
+	filterDoc, err := renderDoc(m, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	filterDoc.writer = w
+	searcher := NewSearcher("line 2999", nil, false, false)
+	m.searchWriter(context.Background(), searcher, filterDoc, 0)
+	for !filterDoc.BufEOF() {
+	}
+	if filterDoc.BufEndNum() != 11 {
+		t.Fatalf("BufEndNum() = %d, want 11", filterDoc.BufEndNum())
+	}
+	for n, want := range map[int]string{0: "line 2999", 1: "line 29990", 10: "line 29999"} {
+		if got := filterDoc.LineString(n); got != want {
+			t.Errorf("LineString(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
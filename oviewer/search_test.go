@@ -587,6 +587,45 @@ func TestRoot_setSearch(t *testing.T) {
 				word: "Test",
 			},
 		},
+		{
+			name: "testBooleanSearch",
+			config: Config{
+				BooleanSearch: true,
+			},
+			fields: fields{
+				input: &Input{},
+			},
+			args: args{
+				word:          "error & !Timeout",
+				caseSensitive: false,
+			},
+			want: booleanWord{
+				word: "error & !Timeout",
+				groups: [][]booleanTerm{
+					{
+						{searcher: searchWord{word: "error"}},
+						{searcher: searchWord{word: "timeout"}, not: true},
+					},
+				},
+			},
+		},
+		{
+			name: "testFuzzySearch",
+			config: Config{
+				FuzzySearch: true,
+			},
+			fields: fields{
+				input: &Input{},
+			},
+			args: args{
+				word:          "kAPI",
+				caseSensitive: false,
+			},
+			want: fuzzyWord{
+				word:    "kAPI",
+				pattern: []rune("kapi"),
+			},
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -603,6 +642,35 @@ func TestRoot_setSearch(t *testing.T) {
 	}
 }
 
+func TestRoot_setSearcherConflict(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root, err := openFiles([]string{filepath.Join(testdata, "test.txt")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	root.Config.FuzzySearch = true
+	root.Config.BooleanSearch = true
+	if got := root.setSearcher("test", false); got != nil {
+		t.Errorf("Root.setSearcher() = %v, want nil", got)
+	}
+	if want := "search: " + ErrSearchConflict.Error(); root.message != want {
+		t.Errorf("Root.setSearcher() message = %q, want %q", root.message, want)
+	}
+
+	root.Config.BooleanSearch = false
+	root.inputBooleanSearch()
+	if root.Config.FuzzySearch || !root.Config.BooleanSearch {
+		t.Errorf("inputBooleanSearch() fuzzy = %v, boolean = %v, want false, true", root.Config.FuzzySearch, root.Config.BooleanSearch)
+	}
+	root.inputFuzzySearch()
+	if !root.Config.FuzzySearch || root.Config.BooleanSearch {
+		t.Errorf("inputFuzzySearch() fuzzy = %v, boolean = %v, want true, false", root.Config.FuzzySearch, root.Config.BooleanSearch)
+	}
+}
+
 func Test_multiRegexpCompile(t *testing.T) {
 	t.Parallel()
 	type args struct {
@@ -920,3 +988,50 @@ func Test_search_String(t *testing.T) {
 		})
 	}
 }
+
+func Test_stripEscapeSequenceString(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		str  string
+		want string
+	}{
+		{
+			name: "testCRLF",
+			str:  "foo bar\r",
+			want: "foo bar",
+		},
+		{
+			name: "testCRMiddle",
+			str:  "foo\rbar\r",
+			want: "foobar",
+		},
+		{
+			name: "testCRLFEscape",
+			str:  "\x1b[31mfoo\x1b[0m\r",
+			want: "foo",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := stripEscapeSequenceString(tt.str); got != tt.want {
+				t.Errorf("stripEscapeSequenceString() = %q, want %q", got, tt.want)
+			}
+			if got := stripEscapeSequenceBytes([]byte(tt.str)); string(got) != tt.want {
+				t.Errorf("stripEscapeSequenceBytes() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_stripEscapeSequenceStringCRLF(t *testing.T) {
+	// CRLF lines are not parsed.
+	allocs := testing.AllocsPerRun(100, func() {
+		stripEscapeSequenceString("foo bar\r")
+	})
+	if allocs != 0 {
+		t.Errorf("stripEscapeSequenceString() allocs = %v, want 0", allocs)
+	}
+}
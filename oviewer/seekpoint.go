@@ -0,0 +1,386 @@
+package oviewer
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// seekPoint is a position in the compressed file where decompression can be restarted.
+type seekPoint struct {
+	// header is the stream header to prepend (xz block only).
+	header []byte
+	// cOffset is the offset in the compressed file.
+	cOffset int64
+	// uOffset is the offset in the uncompressed contents.
+	uOffset int64
+	// streamEnd is the offset of the end of the stream in the compressed file (xz block only).
+	streamEnd int64
+	// remain is the number of uncompressed bytes up to the end of the stream (xz block only).
+	remain int64
+}
+
+// seekPoints holds the seek points of a compressed regular file.
+// Chunks evicted from memory are reloaded by decompressing from the nearest seek point.
+// The start of the file is always a seek point,
+// so formats without an index (bzip2, lz4, single member gzip) are read from the beginning.
+type seekPoints struct {
+	file    *os.File
+	points  []seekPoint
+	cFormat Compressed
+}
+
+// newSeekPoints returns the seek points of the compressed file.
+// The index of xz and the seek table of zstd (seekable format) are read from the end of the file.
+func newSeekPoints(cFormat Compressed, file *os.File) *seekPoints {
+	sp := &seekPoints{
+		cFormat: cFormat,
+		file:    file,
+		points:  []seekPoint{{cOffset: 0, uOffset: 0}},
+	}
+	fi, err := file.Stat()
+	if err != nil {
+		return sp
+	}
+	var points []seekPoint
+	switch cFormat {
+	case XZ:
+		points, err = xzSeekPoints(file, fi.Size())
+	case ZSTD:
+		points, err = zstdSeekPoints(file, fi.Size())
+	}
+	if err == nil && len(points) > 0 {
+		sp.points = points
+	}
+	return sp
+}
+
+// add adds a seek point found while reading (gzip member).
+func (sp *seekPoints) add(cOffset int64, uOffset int64) {
+	last := sp.points[len(sp.points)-1]
+	if uOffset <= last.uOffset {
+		return
+	}
+	sp.points = append(sp.points, seekPoint{cOffset: cOffset, uOffset: uOffset})
+}
+
+// open returns a reader of the uncompressed contents from uOffset.
+func (sp *seekPoints) open(uOffset int64) (io.ReadCloser, error) {
+	i := sort.Search(len(sp.points), func(i int) bool {
+		return sp.points[i].uOffset > uOffset
+	}) - 1
+	p := sp.points[max(i, 0)]
+
+	r, err := sp.reader(p)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.CopyN(io.Discard, r, uOffset-p.uOffset); err != nil {
+		r.Close()
+		return nil, fmt.Errorf("skip to %d: %w", uOffset, err)
+	}
+	return r, nil
+}
+
+// reader returns a reader that decompresses from the seek point.
+// The file is read with ReadAt so that it does not affect the sequential reader.
+func (sp *seekPoints) reader(p seekPoint) (io.ReadCloser, error) {
+	section := func(start int64) io.Reader {
+		return io.NewSectionReader(sp.file, start, 1<<63-1-start)
+	}
+	switch sp.cFormat {
+	case XZ:
+		if p.header == nil {
+			r, err := xz.NewReader(section(p.cOffset))
+			return io.NopCloser(r), err
+		}
+		// Resume from the block by prepending the stream header.
+		br, err := xz.NewReader(io.MultiReader(bytes.NewReader(p.header), section(p.cOffset)))
+		if err != nil {
+			return nil, err
+		}
+		r := io.MultiReader(io.LimitReader(br, p.remain), &lazyReader{open: func() (io.Reader, error) {
+			return xz.NewReader(section(p.streamEnd))
+		}})
+		return io.NopCloser(r), nil
+	case ZSTD:
+		d, err := zstd.NewReader(section(p.cOffset), zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		return d.IOReadCloser(), nil
+	}
+	return io.NopCloser(compressedFormatReader(sp.cFormat, section(p.cOffset))), nil
+}
+
+// lazyReader opens the reader at the first read.
+// It is used for the streams following an xz block, which may not exist.
+type lazyReader struct {
+	r    io.Reader
+	open func() (io.Reader, error)
+}
+
+// Read opens the reader if necessary and reads from it.
+func (l *lazyReader) Read(p []byte) (int, error) {
+	if l.r == nil {
+		r, err := l.open()
+		if err != nil {
+			if errors.Is(err, io.ErrUnexpectedEOF) {
+				return 0, io.EOF
+			}
+			return 0, err
+		}
+		l.r = r
+	}
+	return l.r.Read(p)
+}
+
+// xz format constants.
+const (
+	xzHeaderLen = 12
+	xzFooterLen = 12
+)
+
+// xzSeekPoints returns the start of each block from the index of the xz file.
+// Streams are read backward from the end of the file.
+func xzSeekPoints(r io.ReaderAt, size int64) ([]seekPoint, error) {
+	type stream struct {
+		header []byte
+		start  int64
+		end    int64
+		blocks []int64 // compressed offset of each block.
+		sizes  []int64 // uncompressed size of each block.
+	}
+	var streams []stream
+	end := size
+	// next is the start of the following stream (after the padding).
+	next := size
+	for end > 0 {
+		// Skip the stream padding.
+		pad := make([]byte, 4)
+		if _, err := r.ReadAt(pad, end-4); err != nil {
+			return nil, err
+		}
+		if bytes.Equal(pad, []byte{0, 0, 0, 0}) {
+			end -= 4
+			continue
+		}
+
+		footer := make([]byte, xzFooterLen)
+		if end < xzHeaderLen+xzFooterLen {
+			return nil, ErrNotFound
+		}
+		if _, err := r.ReadAt(footer, end-xzFooterLen); err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(footer[10:], []byte("YZ")) {
+			return nil, fmt.Errorf("xz footer %w", ErrNotFound)
+		}
+		indexSize := (int64(binary.LittleEndian.Uint32(footer[4:8])) + 1) * 4
+		indexStart := end - xzFooterLen - indexSize
+		if indexStart < xzHeaderLen {
+			return nil, fmt.Errorf("xz index %w", ErrOutOfRange)
+		}
+		index := make([]byte, indexSize)
+		if _, err := r.ReadAt(index, indexStart); err != nil {
+			return nil, err
+		}
+		unpadded, uncompressed, err := parseXZIndex(index)
+		if err != nil {
+			return nil, err
+		}
+
+		var blocksSize int64
+		for _, u := range unpadded {
+			blocksSize += (u + 3) &^ 3
+		}
+		start := indexStart - blocksSize - xzHeaderLen
+		if start < 0 {
+			return nil, fmt.Errorf("xz blocks %w", ErrOutOfRange)
+		}
+		header := make([]byte, xzHeaderLen)
+		if _, err := r.ReadAt(header, start); err != nil {
+			return nil, err
+		}
+		if compressType(header) != XZ {
+			return nil, fmt.Errorf("xz header %w", ErrNotFound)
+		}
+
+		s := stream{header: header, start: start, end: next, sizes: uncompressed}
+		offset := start + xzHeaderLen
+		for _, u := range unpadded {
+			s.blocks = append(s.blocks, offset)
+			offset += (u + 3) &^ 3
+		}
+		streams = append([]stream{s}, streams...)
+		end, next = start, start
+	}
+
+	var points []seekPoint
+	var uOffset int64
+	for _, s := range streams {
+		var total int64
+		for _, n := range s.sizes {
+			total += n
+		}
+		remain := total
+		for i, cOffset := range s.blocks {
+			p := seekPoint{cOffset: cOffset, uOffset: uOffset, header: s.header, streamEnd: s.end, remain: remain}
+			if i == 0 {
+				// The start of the stream can be read as it is.
+				p = seekPoint{cOffset: s.start, uOffset: uOffset}
+			}
+			points = append(points, p)
+			uOffset += s.sizes[i]
+			remain -= s.sizes[i]
+		}
+	}
+	return points, nil
+}
+
+// parseXZIndex parses the index of the xz stream
+// and returns the unpadded size and the uncompressed size of each block.
+func parseXZIndex(index []byte) ([]int64, []int64, error) {
+	if len(index) == 0 || index[0] != 0 {
+		return nil, nil, fmt.Errorf("xz index indicator %w", ErrNotFound)
+	}
+	br := bytes.NewReader(index[1:])
+	num, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, nil, fmt.Errorf("xz index: %w", err)
+	}
+	if num > uint64(len(index)) {
+		return nil, nil, fmt.Errorf("xz index records %w", ErrOutOfRange)
+	}
+	unpadded := make([]int64, 0, num)
+	uncompressed := make([]int64, 0, num)
+	for i := uint64(0); i < num; i++ {
+		u, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, nil, fmt.Errorf("xz index: %w", err)
+		}
+		c, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, nil, fmt.Errorf("xz index: %w", err)
+		}
+		unpadded = append(unpadded, int64(u))
+		uncompressed = append(uncompressed, int64(c))
+	}
+	return unpadded, uncompressed, nil
+}
+
+// zstd seekable format constants.
+const (
+	zstdSeekableMagic  = 0x8F92EAB1
+	zstdSkippableMagic = 0x184D2A5E
+	zstdSeekFooterLen  = 9
+)
+
+// zstdSeekPoints returns the start of each frame from the seek table of the zstd seekable format.
+func zstdSeekPoints(r io.ReaderAt, size int64) ([]seekPoint, error) {
+	if size < zstdSeekFooterLen+8 {
+		return nil, ErrNotFound
+	}
+	footer := make([]byte, zstdSeekFooterLen)
+	if _, err := r.ReadAt(footer, size-zstdSeekFooterLen); err != nil {
+		return nil, err
+	}
+	if binary.LittleEndian.Uint32(footer[5:]) != zstdSeekableMagic {
+		return nil, fmt.Errorf("zstd seek table %w", ErrNotFound)
+	}
+	num := int64(binary.LittleEndian.Uint32(footer[:4]))
+	entrySize := int64(8)
+	if footer[4]&0x80 != 0 {
+		entrySize = 12
+	}
+	tableStart := size - zstdSeekFooterLen - num*entrySize - 8
+	if tableStart < 0 {
+		return nil, fmt.Errorf("zstd seek table %w", ErrOutOfRange)
+	}
+	table := make([]byte, num*entrySize+8)
+	if _, err := r.ReadAt(table, tableStart); err != nil {
+		return nil, err
+	}
+	if binary.LittleEndian.Uint32(table[:4]) != zstdSkippableMagic {
+		return nil, fmt.Errorf("zstd skippable frame %w", ErrNotFound)
+	}
+
+	points := make([]seekPoint, 0, num)
+	var cOffset, uOffset int64
+	for i := int64(0); i < num; i++ {
+		entry := table[8+i*entrySize:]
+		points = append(points, seekPoint{cOffset: cOffset, uOffset: uOffset})
+		cOffset += int64(binary.LittleEndian.Uint32(entry[:4]))
+		uOffset += int64(binary.LittleEndian.Uint32(entry[4:8]))
+	}
+	if cOffset != tableStart {
+		return nil, fmt.Errorf("zstd seek table %w", ErrOutOfRange)
+	}
+	return points, nil
+}
+
+// gzipMembers is a gzip reader that adds the start of each member as a seek point.
+type gzipMembers struct {
+	sp *seekPoints
+	cr *countReader
+	br *bufio.Reader
+	zr *gzip.Reader
+	// size is the number of uncompressed bytes read.
+	size int64
+}
+
+// countReader is a reader that counts the number of bytes read.
+type countReader struct {
+	r io.Reader
+	n int64
+}
+
+// Read reads from the underlying reader and counts the bytes.
+func (c *countReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// newGzipMembers returns a reader that decompresses the gzip file member by member.
+func newGzipMembers(sp *seekPoints, r io.Reader) (io.Reader, error) {
+	cr := &countReader{r: r}
+	br := bufio.NewReader(cr)
+	zr, err := gzip.NewReader(br)
+	if err != nil {
+		return nil, err
+	}
+	zr.Multistream(false)
+	return &gzipMembers{sp: sp, cr: cr, br: br, zr: zr}, nil
+}
+
+// Read reads the uncompressed contents and continues to the next member.
+func (g *gzipMembers) Read(p []byte) (int, error) {
+	for {
+		n, err := g.zr.Read(p)
+		g.size += int64(n)
+		if !errors.Is(err, io.EOF) {
+			return n, err
+		}
+		// The gzip reader reads the bufio.Reader directly,
+		// so the start of the next member is the bytes read minus the buffered bytes.
+		cOffset := g.cr.n - int64(g.br.Buffered())
+		if err := g.zr.Reset(g.br); err != nil {
+			return n, err
+		}
+		g.zr.Multistream(false)
+		g.sp.add(cOffset, g.size)
+		if n > 0 {
+			return n, nil
+		}
+	}
+}
@@ -0,0 +1,292 @@
+package oviewer
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// gzipMembersFile writes the contents as gzip members of size bytes each.
+func gzipMembersFile(t *testing.T, contents []byte, size int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	for start := 0; start < len(contents); start += size {
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(contents[start:min(start+size, len(contents))]); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return buf.Bytes()
+}
+
+// xzBlocksFile writes the contents as xz streams divided into blocks.
+func xzBlocksFile(t *testing.T, contents []byte, streams int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	size := (len(contents) + streams - 1) / streams
+	for start := 0; start < len(contents); start += size {
+		w, err := xz.WriterConfig{BlockSize: 100000}.NewWriter(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(contents[start:min(start+size, len(contents))]); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		// stream padding.
+		buf.Write([]byte{0, 0, 0, 0})
+	}
+	return buf.Bytes()
+}
+
+// zstdFile writes the contents as a single zstd frame without a seek table.
+func zstdFile(t *testing.T, contents []byte) []byte {
+	t.Helper()
+	enc, err := zstd.NewWriter(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return enc.EncodeAll(contents, nil)
+}
+
+// zstdSeekableFile writes the contents as zstd frames of size bytes each with a seek table.
+func zstdSeekableFile(t *testing.T, contents []byte, size int) []byte {
+	t.Helper()
+	enc, err := zstd.NewWriter(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf, table bytes.Buffer
+	num := 0
+	for start := 0; start < len(contents); start += size {
+		frame := enc.EncodeAll(contents[start:min(start+size, len(contents))], nil)
+		buf.Write(frame)
+		_ = binary.Write(&table, binary.LittleEndian, uint32(len(frame)))
+		_ = binary.Write(&table, binary.LittleEndian, uint32(min(size, len(contents)-start)))
+		num++
+	}
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(zstdSkippableMagic))
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(table.Len()+zstdSeekFooterLen))
+	buf.Write(table.Bytes())
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(num))
+	buf.WriteByte(0)
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(zstdSeekableMagic))
+	return buf.Bytes()
+}
+
+func writeTemp(t *testing.T, name string, b []byte) *os.File {
+	t.Helper()
+	fileName := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(fileName, b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { f.Close() })
+	return f
+}
+
+func TestSeekPoints_open(t *testing.T) {
+	contents := []byte(numberLines(30000))
+	tests := []struct {
+		name       string
+		cFormat    Compressed
+		data       []byte
+		wantPoints int
+	}{
+		{
+			name:       "testGzipMembers",
+			cFormat:    GZIP,
+			data:       gzipMembersFile(t, contents, 300000),
+			wantPoints: 5,
+		},
+		{
+			name:       "testGzipSingle",
+			cFormat:    GZIP,
+			data:       gzipMembersFile(t, contents, len(contents)),
+			wantPoints: 1,
+		},
+		{
+			name:       "testXZBlocks",
+			cFormat:    XZ,
+			data:       xzBlocksFile(t, contents, 1),
+			wantPoints: 15,
+		},
+		{
+			name:       "testXZStreams",
+			cFormat:    XZ,
+			data:       xzBlocksFile(t, contents, 3),
+			wantPoints: 15,
+		},
+		{
+			name:       "testZstdSeekable",
+			cFormat:    ZSTD,
+			data:       zstdSeekableFile(t, contents, 200000),
+			wantPoints: 8,
+		},
+		{
+			name:       "testZstdNoTable",
+			cFormat:    ZSTD,
+			data:       zstdFile(t, contents),
+			wantPoints: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := writeTemp(t, "test", tt.data)
+			sp := newSeekPoints(tt.cFormat, f)
+			if tt.cFormat == GZIP {
+				r, err := newGzipMembers(sp, f)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got, err := io.ReadAll(r)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(got, contents) {
+					t.Fatalf("gzipMembers contents mismatch")
+				}
+			}
+			if len(sp.points) != tt.wantPoints {
+				t.Errorf("len(points) = %d, want %d", len(sp.points), tt.wantPoints)
+			}
+			for _, offset := range []int64{0, 1, 199999, 300000, 550000, int64(len(contents)) - 10} {
+				r, err := sp.open(offset)
+				if err != nil {
+					t.Fatalf("open(%d) %v", offset, err)
+				}
+				got, err := io.ReadAll(r)
+				r.Close()
+				if err != nil {
+					t.Fatalf("open(%d) read %v", offset, err)
+				}
+				if !bytes.Equal(got, contents[offset:]) {
+					t.Errorf("open(%d) contents mismatch len %d, want %d", offset, len(got), len(contents)-int(offset))
+				}
+			}
+		})
+	}
+}
+
+func TestGzipMembers_checksum(t *testing.T) {
+	data := gzipMembersFile(t, []byte(numberLines(1000)), 100000)
+	// Corrupt the CRC-32 of the trailer.
+	data[len(data)-8] ^= 0xff
+	f := writeTemp(t, "test.gz", data)
+	r, err := newGzipMembers(newSeekPoints(GZIP, f), f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadAll(r); !errors.Is(err, gzip.ErrChecksum) {
+		t.Errorf("ReadAll() error = %v, want %v", err, gzip.ErrChecksum)
+	}
+}
+
+func TestDocument_fileReaderNoMemoryLimit(t *testing.T) {
+	defer func(limit int) {
+		MemoryLimit = limit
+	}(MemoryLimit)
+	MemoryLimit = -1
+
+	contents := []byte(numberLines(1000))
+	f := writeTemp(t, "test.gz", gzipMembersFile(t, contents, 1000))
+	m, err := NewDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := m.fileReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.seekPoints != nil {
+		t.Error("seekPoints is not nil without MemoryLimit")
+	}
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, contents) {
+		t.Errorf("fileReader() contents mismatch")
+	}
+}
+
+func TestDocument_loadReadCompressed(t *testing.T) {
+	defer func(limit int) {
+		MemoryLimit = limit
+	}(MemoryLimit)
+	MemoryLimit = 2
+
+	contents := []byte(numberLines(55000))
+	f := writeTemp(t, "test.gz", gzipMembersFile(t, contents, 100000))
+	m, err := NewDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.store.setNewLoadChunks(loadChunksCapacity(false))
+	r, err := m.fileReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.seekPoints == nil {
+		t.Fatal("seekPoints is nil")
+	}
+	reader := bufio.NewReader(r)
+	if reader, err = m.firstRead(reader); err != nil {
+		t.Fatal(err)
+	}
+	// Read to the end, evicting the old chunks.
+	for !m.BufEOF() {
+		if reader, err = m.continueRead(reader); err != nil {
+			t.Fatal(err)
+		}
+		if reader, err = m.loadRead(reader, m.store.lastChunkNum()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if m.BufEndNum() != 55000 {
+		t.Fatalf("BufEndNum() = %d, want 55000", m.BufEndNum())
+	}
+	if m.BufStartNum() != 0 {
+		t.Errorf("BufStartNum() = %d, want 0", m.BufStartNum())
+	}
+	if !m.store.isUnloadedChunk(1) {
+		t.Fatal("chunk 1 is not evicted")
+	}
+
+	lines := bytes.SplitAfter(contents, []byte("\n"))
+	for _, chunkNum := range []int{1, 3, 2, 1} {
+		if _, err := m.loadRead(reader, chunkNum); err != nil {
+			t.Fatal(err)
+		}
+		for _, cn := range []int{0, 1, ChunkSize - 1} {
+			n := chunkNum*ChunkSize + cn
+			got, err := m.store.GetChunkLine(chunkNum, cn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := bytes.TrimSuffix(lines[n], []byte("\n")); !bytes.Equal(got, want) {
+				t.Errorf("line %d = %q, want %q", n, got, want)
+			}
+		}
+	}
+	if m.store.loadedChunks.Len() > MemoryLimit {
+		t.Errorf("loadedChunks = %d, want <= %d", m.store.loadedChunks.Len(), MemoryLimit)
+	}
+}
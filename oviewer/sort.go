@@ -0,0 +1,274 @@
+package oviewer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sortType is the type of the sort key.
+type sortType int
+
+const (
+	sortLexical sortType = iota // sortLexical compares the strings.
+	sortNumeric                 // sortNumeric compares the numbers.
+	sortSize                    // sortSize compares the human-readable sizes such as 1.5K and 20M.
+	sortTime                    // sortTime compares the timestamps.
+)
+
+// sortTypes is the names of the sort types of the input.
+var sortTypes = map[string]sortType{
+	"lexical": sortLexical,
+	"numeric": sortNumeric,
+	"size":    sortSize,
+	"time":    sortTime,
+}
+
+// sortTimeLayouts is the layouts of the timestamps that can be sorted.
+var sortTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006/01/02 15:04:05",
+	"2006-01-02",
+	"02/Jan/2006:15:04:05 -0700",
+	time.RFC1123Z,
+	time.RFC1123,
+	time.ANSIC,
+	time.Stamp,
+	"15:04:05",
+	"15:04",
+}
+
+// sortLine is a line of the document to sort.
+type sortLine struct {
+	line  []byte
+	value string
+	num   int
+	key   float64
+	valid bool
+}
+
+// Sort fires the sort event.
+// The type is "lexical", "numeric", "size" or "time",
+// and the "-" prefix sorts in descending order.
+func (root *Root) Sort(typ string) {
+	root.input.value = typ
+	ev := &eventInputSort{
+		value: typ,
+	}
+	root.postEvent(ev)
+}
+
+// sortColumn sorts the document by the column of the cursor into a new document.
+// The whole line is the key if not in column mode.
+func (root *Root) sortColumn(ctx context.Context) {
+	typ, desc, err := parseSortType(root.input.value)
+	if err != nil {
+		root.setMessagef("sort %s: %s", root.input.value, err.Error())
+		return
+	}
+
+	m := root.Doc
+	value := stripEscapeSequenceString
+	target := "line"
+	if m.ColumnMode {
+		cw, ok := m.cursorColumn()
+		if !ok {
+			root.setMessagef("sort: %s", ErrNoColumn.Error())
+			return
+		}
+		value = cw.columnValue
+		target = fmt.Sprintf("column %d", cw.column+1)
+		if cw.key != "" {
+			target = cw.key
+		}
+	}
+	word := fmt.Sprintf("%s:%s", target, strings.TrimSpace(root.input.value))
+
+	r, w := io.Pipe()
+	sortDoc, err := renderDoc(m, r)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	sortDoc.documentType = DocSort
+	sortDoc.FileName = fmt.Sprintf("sort:%s:%s", m.FileName, word)
+	sortDoc.Caption = fmt.Sprintf("%s:%s", m.FileName, word)
+	root.addDocument(sortDoc.Document)
+	sortDoc.Document.general = mergeGeneral(m.general, sortDoc.Document.general)
+	sortDoc.Header = m.Header
+	sortDoc.SkipLines = m.SkipLines
+	sortDoc.columnCursor = m.columnCursor
+
+	sortDoc.writer = w
+	go m.sortWriter(ctx, value, typ, desc, sortDoc)
+	root.setMessagef("sort:%s%s", word, root.parentJumpHint())
+}
+
+// sortWriter writes the lines of the document sorted by the value of each line.
+// The skipped lines and the header are written as they are.
+// The error is written instead of a partial result if the lines cannot be read.
+func (m *Document) sortWriter(ctx context.Context, value func(string) string, typ sortType, desc bool, sortDoc *renderDocument) {
+	defer sortDoc.writer.Close()
+	head, lines, err := m.sortLines(ctx, value, typ)
+	if err != nil {
+		if !errors.Is(err, ErrCancel) {
+			log.Printf("sort: %s", err)
+			sortDoc.writeLine([]byte(fmt.Sprintf("sort: %s", err)))
+		}
+		return
+	}
+	for n, sl := range head {
+		sortDoc.lineNumMap.Store(n, m.originLineNum(sl.num))
+		sortDoc.writeLine(sl.line)
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		return sortLess(&lines[i], &lines[j], typ, desc)
+	})
+	for n, sl := range lines {
+		sortDoc.lineNumMap.Store(len(head)+n, m.originLineNum(sl.num))
+		sortDoc.writeLine(sl.line)
+	}
+}
+
+// sortLines returns the skipped lines and the header, and the lines to sort.
+// The chunks that are not in memory are loaded in turn.
+func (m *Document) sortLines(ctx context.Context, value func(string) string, typ sortType) ([]sortLine, []sortLine, error) {
+	var head []sortLine
+	lines := make([]sortLine, 0, max(0, m.BufEndNum()-m.firstLine()))
+	// The searcher that matches all lines loads the chunk.
+	loader := NewSearcher("", nil, true, false)
+	startChunk, _ := chunkLineNum(m.BufStartNum())
+	for chunkNum := startChunk; chunkNum <= m.store.lastChunkNum(); chunkNum++ {
+		select {
+		case <-ctx.Done():
+			return nil, nil, ErrCancel
+		default:
+		}
+		if !m.isLoadedChunk(chunkNum) && !m.storageSearch(loader, chunkNum) {
+			return nil, nil, fmt.Errorf("chunk %d %w", chunkNum, ErrNotLoaded)
+		}
+		_, end := m.store.chunkRange(chunkNum)
+		for n := 0; n < end; n++ {
+			ln := chunkNum*ChunkSize + n
+			if ln < m.BufStartNum() {
+				continue
+			}
+			line, err := m.store.GetChunkLine(chunkNum, n)
+			if err != nil {
+				return nil, nil, fmt.Errorf("line %d %w", ln, ErrNotLoaded)
+			}
+			if ln < m.firstLine() {
+				head = append(head, sortLine{line: line, num: ln})
+				continue
+			}
+			sl := sortLine{line: line, num: ln, value: value(string(line))}
+			if typ != sortLexical {
+				sl.key, sl.valid = sortKey(sl.value, typ)
+			}
+			lines = append(lines, sl)
+		}
+	}
+	return head, lines, nil
+}
+
+// originLineNum returns the line number of the original document.
+func (m *Document) originLineNum(lN int) int {
+	if m.lineNumMap != nil {
+		if n, ok := m.lineNumMap.LoadForward(lN); ok {
+			return n
+		}
+	}
+	return lN
+}
+
+// parseSortType returns the sort type and whether it is descending from the input.
+// The empty type is lexical.
+func parseSortType(str string) (sortType, bool, error) {
+	str = strings.TrimSpace(str)
+	desc := strings.HasPrefix(str, "-")
+	str = strings.TrimPrefix(str, "-")
+	if str == "" {
+		return sortLexical, desc, nil
+	}
+	typ, ok := sortTypes[str]
+	if !ok {
+		return sortLexical, desc, ErrInvalidSortType
+	}
+	return typ, desc, nil
+}
+
+// sortLess returns true if the line a is sorted before the line b.
+// The lines without a valid key are placed at the end in both orders.
+func sortLess(a *sortLine, b *sortLine, typ sortType, desc bool) bool {
+	if typ == sortLexical {
+		if desc {
+			return a.value > b.value
+		}
+		return a.value < b.value
+	}
+	if a.valid != b.valid {
+		return a.valid
+	}
+	if !a.valid {
+		return false
+	}
+	if desc {
+		return a.key > b.key
+	}
+	return a.key < b.key
+}
+
+// sortKey returns the numerical key of the value for the sort type.
+// It returns false if the value cannot be converted.
+func sortKey(value string, typ sortType) (float64, bool) {
+	value = strings.TrimSpace(value)
+	switch typ {
+	case sortNumeric:
+		n, err := strconv.ParseFloat(strings.ReplaceAll(value, ",", ""), 64)
+		return n, err == nil
+	case sortSize:
+		return parseSize(value)
+	case sortTime:
+		for _, layout := range sortTimeLayouts {
+			if t, err := time.Parse(layout, value); err == nil {
+				return float64(t.UnixNano()), true
+			}
+		}
+	}
+	return 0, false
+}
+
+// parseSize returns the number of bytes of the human-readable size.
+// The units are K, M, G, T, P and E in powers of 1024,
+// and the suffixes such as "iB" and "B" are allowed.
+func parseSize(str string) (float64, bool) {
+	i := 0
+	for i < len(str) && (str[i] >= '0' && str[i] <= '9' || str[i] == '.') {
+		i++
+	}
+	n, err := strconv.ParseFloat(str[:i], 64)
+	if err != nil {
+		return 0, false
+	}
+	unit := strings.ToUpper(strings.TrimSpace(str[i:]))
+	unit = strings.TrimSuffix(unit, "B")
+	unit = strings.TrimSuffix(unit, "I")
+	if unit == "" {
+		return n, true
+	}
+	p := strings.Index("KMGTPE", unit)
+	if len(unit) != 1 || p < 0 {
+		return 0, false
+	}
+	return n * math.Pow(1024, float64(p+1)), true
+}
@@ -0,0 +1,193 @@
+package oviewer
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_parseSortType(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		str      string
+		wantType sortType
+		wantDesc bool
+		wantErr  bool
+	}{
+		{name: "testEmpty", str: "", wantType: sortLexical},
+		{name: "testNumeric", str: "numeric", wantType: sortNumeric},
+		{name: "testDescSize", str: " -size", wantType: sortSize, wantDesc: true},
+		{name: "testDescOnly", str: "-", wantType: sortLexical, wantDesc: true},
+		{name: "testInvalid", str: "random", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			typ, desc, err := parseSortType(tt.str)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSortType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if typ != tt.wantType || desc != tt.wantDesc {
+				t.Errorf("parseSortType() = %v, %v, want %v, %v", typ, desc, tt.wantType, tt.wantDesc)
+			}
+		})
+	}
+}
+
+func Test_sortKey(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		value     string
+		typ       sortType
+		want      float64
+		wantValid bool
+	}{
+		{name: "testNumeric", value: " 1,024.5 ", typ: sortNumeric, want: 1024.5, wantValid: true},
+		{name: "testNumericInvalid", value: "abc", typ: sortNumeric},
+		{name: "testSizeBytes", value: "512", typ: sortSize, want: 512, wantValid: true},
+		{name: "testSizeK", value: "1.5K", typ: sortSize, want: 1536, wantValid: true},
+		{name: "testSizeMiB", value: "2MiB", typ: sortSize, want: 2 * 1024 * 1024, wantValid: true},
+		{name: "testSizeLower", value: "1 gb", typ: sortSize, want: 1024 * 1024 * 1024, wantValid: true},
+		{name: "testSizeInvalid", value: "1X", typ: sortSize},
+		{name: "testTimeDate", value: "1970-01-02", typ: sortTime, want: 86400 * 1e9, wantValid: true},
+		{name: "testTimeRFC3339", value: "1970-01-01T00:00:01Z", typ: sortTime, want: 1e9, wantValid: true},
+		{name: "testTimeInvalid", value: "yesterday", typ: sortTime},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, valid := sortKey(tt.value, tt.typ)
+			if valid != tt.wantValid {
+				t.Fatalf("sortKey() valid = %v, want %v", valid, tt.wantValid)
+			}
+			if valid && got != tt.want {
+				t.Errorf("sortKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocument_sortWriter(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		typ        sortType
+		desc       bool
+		want       []string
+		wantOrigin []int
+	}{
+		{
+			name:       "testLexical",
+			typ:        sortLexical,
+			want:       []string{"name,size", "a,10K", "b,2M", "c,-", "d,512"},
+			wantOrigin: []int{0, 2, 4, 3, 1},
+		},
+		{
+			name:       "testSize",
+			typ:        sortSize,
+			want:       []string{"name,size", "d,512", "a,10K", "b,2M", "c,-"},
+			wantOrigin: []int{0, 1, 2, 4, 3},
+		},
+		{
+			name:       "testSizeDesc",
+			typ:        sortSize,
+			desc:       true,
+			want:       []string{"name,size", "b,2M", "a,10K", "d,512", "c,-"},
+			wantOrigin: []int{0, 4, 2, 1, 3},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			m, err := NewDocument()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := m.ControlReader(strings.NewReader("name,size\nd,512\na,10K\nc,-\nb,2M\n"), nil); err != nil {
+				t.Fatal(err)
+			}
+			for !m.BufEOF() {
+			}
+			m.Header = 1
+			m.ColumnDelimiter = ","
+			m.columnCursor = 1
+			if tt.typ == sortLexical {
+				m.columnCursor = 0
+			}
+			cw, ok := m.cursorColumn()
+			if !ok {
+				t.Fatal("cursorColumn() = false")
+			}
+
+			r, w := io.Pipe()
+			sortDoc, err := renderDoc(m, r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sortDoc.writer = w
+			m.sortWriter(context.Background(), cw.columnValue, tt.typ, tt.desc, sortDoc)
+			for !sortDoc.BufEOF() {
+			}
+
+			if sortDoc.BufEndNum() != len(tt.want) {
+				t.Fatalf("BufEndNum() = %d, want %d", sortDoc.BufEndNum(), len(tt.want))
+			}
+			for n, want := range tt.want {
+				if got := sortDoc.LineString(n); got != want {
+					t.Errorf("LineString(%d) = %q, want %q", n, got, want)
+				}
+				if got, _ := sortDoc.lineNumMap.LoadForward(n); got != tt.wantOrigin[n] {
+					t.Errorf("lineNumMap(%d) = %d, want %d", n, got, tt.wantOrigin[n])
+				}
+			}
+		})
+	}
+}
+
+func TestDocument_sortWriterEvicted(t *testing.T) {
+	t.Parallel()
+	m := openEOF(t, parallelSearchFile(t, 25000))
+	// The evicted chunk is loaded again.
+	m.store.unloadChunk(1)
+	m.ColumnMode = true
+	m.setDelimiter(" ")
+	m.columnCursor = 1
+	cw, ok := m.cursorColumn()
+	if !ok {
+		t.Fatal("cursorColumn() = false")
+	}
+
+	r, w := io.Pipe()
+	sortDoc, err := renderDoc(m, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sortDoc.writer = w
+	go m.sortWriter(context.Background(), cw.columnValue, sortNumeric, true, sortDoc)
+	for !sortDoc.BufEOF() {
+		time.Sleep(time.Millisecond)
+	}
+	if sortDoc.BufEndNum() != 25000 {
+		t.Fatalf("BufEndNum() = %d, want %d", sortDoc.BufEndNum(), 25000)
+	}
+	for n, want := range map[int]string{0: "line 24999", 14999: "line 10000", 24999: "line 0"} {
+		chunkNum, cn := chunkLineNum(n)
+		got, err := sortDoc.store.GetChunkLine(chunkNum, cn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("line %d = %q, want %q", n, got, want)
+		}
+	}
+}
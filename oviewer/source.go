@@ -0,0 +1,196 @@
+package oviewer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Source is the input source of a Document.
+// A Document reads the Source with ControlSource.
+//
+// The lines are read from the reader returned by Open.
+// If Seekable returns true, the chunks evicted from memory are read again with ReadAt,
+// otherwise they are discarded (or written to the spool if Spool is enabled).
+type Source interface {
+	// Open returns a reader that reads the source from the beginning.
+	// Open is called again when the document is reloaded.
+	Open() (io.Reader, error)
+	// ReadAt reads the source from the byte offset.
+	// ReadAt is called only if Seekable returns true.
+	io.ReaderAt
+	// Size returns the current size of the source, or -1 if it is unknown.
+	// A seekable source that has grown is read from the end in follow mode.
+	Size() int64
+	// Seekable returns true if the source can be read with ReadAt.
+	Seekable() bool
+}
+
+// readerAtSource is a seekable Source of io.ReaderAt.
+type readerAtSource struct {
+	r    io.ReaderAt
+	size int64
+}
+
+// NewReaderAtSource returns a seekable Source that reads size bytes of r.
+func NewReaderAtSource(r io.ReaderAt, size int64) Source {
+	return &readerAtSource{r: r, size: size}
+}
+
+// NewBytesSource returns a seekable Source of the byte slice.
+func NewBytesSource(b []byte) Source {
+	return NewReaderAtSource(bytes.NewReader(b), int64(len(b)))
+}
+
+// Open returns a reader from the beginning.
+func (s *readerAtSource) Open() (io.Reader, error) {
+	return io.NewSectionReader(s.r, 0, s.size), nil
+}
+
+// ReadAt reads from the byte offset.
+func (s *readerAtSource) ReadAt(p []byte, off int64) (int, error) {
+	return s.r.ReadAt(p, off)
+}
+
+// Size returns the size.
+func (s *readerAtSource) Size() int64 {
+	return s.size
+}
+
+// Seekable returns true.
+func (s *readerAtSource) Seekable() bool {
+	return true
+}
+
+// readerSource is a non-seekable Source that reads the reader returned by the function.
+type readerSource struct {
+	open func() (io.Reader, error)
+	r    io.Reader
+	mu   sync.Mutex
+}
+
+// NewReaderSource returns a non-seekable Source.
+// open is called to read from the beginning, at the start and when reloading.
+// If the returned reader is an io.Closer, it is closed when it is no longer needed.
+func NewReaderSource(open func() (io.Reader, error)) Source {
+	return &readerSource{open: open}
+}
+
+// Open closes the previous reader and returns a new reader.
+func (s *readerSource) Open() (io.Reader, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.closeReader(); err != nil {
+		log.Printf("close: %s", err)
+	}
+	r, err := s.open()
+	if err != nil {
+		return nil, err
+	}
+	s.r = r
+	return r, nil
+}
+
+// ReadAt is not supported.
+func (s *readerSource) ReadAt(_ []byte, _ int64) (int, error) {
+	return 0, ErrNotSupported
+}
+
+// Size returns -1 because the size is unknown.
+func (s *readerSource) Size() int64 {
+	return -1
+}
+
+// Seekable returns false.
+func (s *readerSource) Seekable() bool {
+	return false
+}
+
+// Close closes the current reader.
+func (s *readerSource) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closeReader()
+}
+
+// closeReader closes the current reader if it is an io.Closer.
+func (s *readerSource) closeReader() error {
+	if s.r == nil {
+		return nil
+	}
+	var err error
+	if c, ok := s.r.(io.Closer); ok {
+		err = c.Close()
+	}
+	s.r = nil
+	return err
+}
+
+// NewSourceDocument returns a Document that reads the Source.
+func NewSourceDocument(src Source) (*Document, error) {
+	m, err := NewDocument()
+	if err != nil {
+		return nil, err
+	}
+	if err := m.ControlSource(src); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// sourceReader returns a reader of the source from the beginning.
+func (m *Document) sourceReader() *bufio.Reader {
+	r, err := m.source.Open()
+	if err != nil {
+		str := fmt.Sprintf("Access is no longer possible: %s", err)
+		return bufio.NewReader(strings.NewReader(str))
+	}
+	return bufio.NewReader(r)
+}
+
+// followSource reads the part of the seekable source that has grown since the last read.
+func (m *Document) followSource(reader *bufio.Reader) (*bufio.Reader, error) {
+	if !m.source.Seekable() || !m.BufEOF() {
+		return reader, nil
+	}
+	m.store.mu.RLock()
+	offset := m.store.size
+	m.store.mu.RUnlock()
+	size := m.source.Size()
+	if size <= offset {
+		return reader, nil
+	}
+	return m.followRead(bufio.NewReader(io.NewSectionReader(m.source, offset, size-offset)))
+}
+
+// reloadSource opens the source again and clears all lines.
+func (m *Document) reloadSource() *bufio.Reader {
+	m.store.loadedChunks.Purge()
+	m.reset()
+	return m.sourceReader()
+}
+
+// closeSource closes the source if it is an io.Closer.
+func (m *Document) closeSource() error {
+	if m.checkClose() {
+		return nil
+	}
+	var err error
+	if c, ok := m.source.(io.Closer); ok {
+		err = c.Close()
+	}
+	if m.spool != nil {
+		if err := m.spool.close(); err != nil {
+			log.Printf("spool: %s", err)
+		}
+	}
+	atomic.StoreInt32(&m.store.eof, 1)
+	atomic.StoreInt32(&m.closed, 1)
+	atomic.StoreInt32(&m.store.changed, 1)
+	return err
+}
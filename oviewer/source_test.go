@@ -0,0 +1,189 @@
+package oviewer
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// growingSource is a seekable Source that can be appended.
+type growingSource struct {
+	buf []byte
+	mu  sync.Mutex
+}
+
+func (s *growingSource) Open() (io.Reader, error) {
+	return io.NewSectionReader(s, 0, s.Size()), nil
+}
+
+func (s *growingSource) ReadAt(p []byte, off int64) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return bytes.NewReader(s.buf).ReadAt(p, off)
+}
+
+func (s *growingSource) Size() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return int64(len(s.buf))
+}
+
+func (s *growingSource) Seekable() bool {
+	return true
+}
+
+func (s *growingSource) append(str string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.buf = append(s.buf, str...)
+}
+
+// closeCountReader counts the number of times it has been closed.
+type closeCountReader struct {
+	io.Reader
+	closed *int
+}
+
+func (r closeCountReader) Close() error {
+	*r.closed++
+	return nil
+}
+
+func TestNewSourceDocument(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		src  Source
+		want []string
+	}{
+		{
+			name: "testBytes",
+			src:  NewBytesSource([]byte("a\nb\nc\n")),
+			want: []string{"a", "b", "c"},
+		},
+		{
+			name: "testReaderAt",
+			src:  NewReaderAtSource(strings.NewReader("a\nb\nc"), 3),
+			want: []string{"a", "b"},
+		},
+		{
+			name: "testReader",
+			src: NewReaderSource(func() (io.Reader, error) {
+				return strings.NewReader("a\nb\nc"), nil
+			}),
+			want: []string{"a", "b", "c"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			m, err := NewSourceDocument(tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for !m.BufEOF() {
+			}
+			if m.BufEndNum() != len(tt.want) {
+				t.Fatalf("BufEndNum() = %d, want %d", m.BufEndNum(), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if got := m.LineString(i); got != want {
+					t.Errorf("LineString(%d) = %q, want %q", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDocument_controlSource(t *testing.T) {
+	defer func(limit int) {
+		MemoryLimit = limit
+	}(MemoryLimit)
+	MemoryLimit = 2
+
+	contents := numberLines(55000)
+	src := &growingSource{}
+	src.append(contents)
+	m, err := NewDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.seekable = false
+	m.source = src
+	m.memoryLimit = loadChunksCapacity(false)
+	m.store.setNewLoadChunks(m.memoryLimit)
+
+	// Read to the end, evicting the old chunks.
+	reader := m.sourceReader()
+	for !m.BufEOF() {
+		if reader, err = m.controlSource(controlSpecifier{request: requestContinue}, reader); err != nil {
+			t.Fatal(err)
+		}
+		if reader, err = m.loadRead(reader, m.store.lastChunkNum()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if m.BufEndNum() != 55000 {
+		t.Fatalf("BufEndNum() = %d, want 55000", m.BufEndNum())
+	}
+	if !m.store.isUnloadedChunk(1) {
+		t.Fatal("chunk 1 is not evicted")
+	}
+
+	lines := strings.SplitAfter(contents, "\n")
+	for _, chunkNum := range []int{1, 3, 2} {
+		if reader, err = m.controlSource(controlSpecifier{request: requestLoad, chunkNum: chunkNum}, reader); err != nil {
+			t.Fatal(err)
+		}
+		n := chunkNum * ChunkSize
+		got, err := m.store.GetChunkLine(chunkNum, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := strings.TrimSuffix(lines[n], "\n"); string(got) != want {
+			t.Errorf("line %d = %q, want %q", n, got, want)
+		}
+	}
+
+	// Follow the grown source.
+	m.FollowMode = true
+	src.append("follow1\nfollow2\n")
+	if _, err = m.controlSource(controlSpecifier{request: requestFollow}, reader); err != nil {
+		t.Fatal(err)
+	}
+	if m.BufEndNum() != 55002 {
+		t.Fatalf("BufEndNum() = %d, want 55002", m.BufEndNum())
+	}
+	if got := m.LineString(55001); got != "follow2" {
+		t.Errorf("LineString(55001) = %q, want follow2", got)
+	}
+}
+
+func TestReaderSource_Close(t *testing.T) {
+	t.Parallel()
+	closed := 0
+	src := NewReaderSource(func() (io.Reader, error) {
+		return closeCountReader{Reader: strings.NewReader("test\n"), closed: &closed}, nil
+	})
+	m, err := NewSourceDocument(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for !m.BufEOF() {
+	}
+	if err := m.reload(); err != nil {
+		t.Fatal(err)
+	}
+	if closed != 1 {
+		t.Errorf("closed = %d after reload, want 1", closed)
+	}
+	if !m.requestClose() {
+		t.Fatal("requestClose() = false")
+	}
+	if closed != 2 {
+		t.Errorf("closed = %d after close, want 2", closed)
+	}
+}
@@ -0,0 +1,121 @@
+package oviewer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// spoolChunk is the position of the chunk written in the spool file.
+type spoolChunk struct {
+	offset int64
+	size   int64
+}
+
+// spool is a temporary file to write the evicted chunks of a non-seekable document.
+// The written chunks are read again when they are needed.
+type spool struct {
+	file   *os.File
+	chunks map[int]spoolChunk
+	size   int64
+	mu     sync.Mutex
+}
+
+// newSpool returns a spool.
+// The temporary file is created when the first chunk is written.
+func newSpool() *spool {
+	return &spool{
+		chunks: make(map[int]spoolChunk),
+	}
+}
+
+// write writes the lines of the chunk to the spool file.
+// The chunk is written only once because evicted chunks are not changed.
+// A newline is added to the line without a newline (e.g. FormFeed) to keep the line boundaries.
+func (sp *spool) write(chunkNum int, lines [][]byte) error {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	if _, ok := sp.chunks[chunkNum]; ok {
+		return nil
+	}
+	if sp.file == nil {
+		f, err := os.CreateTemp("", "ov-spool-")
+		if err != nil {
+			return fmt.Errorf("spool: %w", err)
+		}
+		// Remove it now so that it does not remain (it fails on Windows).
+		_ = os.Remove(f.Name())
+		sp.file = f
+	}
+
+	size, err := sp.writeLines(lines)
+	if err != nil {
+		// Discard the partially written chunk.
+		_, _ = sp.file.Seek(sp.size, io.SeekStart)
+		return fmt.Errorf("spool: %w", err)
+	}
+	sp.chunks[chunkNum] = spoolChunk{offset: sp.size, size: size}
+	sp.size += size
+	return nil
+}
+
+// writeLines writes the lines at the end of the spool file and returns the written size.
+// The file is only appended and read with ReadAt, so the file offset is always at the end.
+func (sp *spool) writeLines(lines [][]byte) (int64, error) {
+	w := bufio.NewWriter(sp.file)
+	var size int64
+	for _, line := range lines {
+		n, err := w.Write(line)
+		if err != nil {
+			return 0, err
+		}
+		size += int64(n)
+		if len(line) == 0 || line[len(line)-1] != '\n' {
+			if err := w.WriteByte('\n'); err != nil {
+				return 0, err
+			}
+			size++
+		}
+	}
+	return size, w.Flush()
+}
+
+// reader returns a reader of the chunk written in the spool file.
+func (sp *spool) reader(chunkNum int) (io.ReadCloser, error) {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	c, ok := sp.chunks[chunkNum]
+	if !ok {
+		return nil, fmt.Errorf("spool chunk %d %w", chunkNum, ErrNotFound)
+	}
+	return io.NopCloser(io.NewSectionReader(sp.file, c.offset, c.size)), nil
+}
+
+// reset discards all the written chunks.
+func (sp *spool) reset() {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	sp.chunks = make(map[int]spoolChunk)
+	sp.size = 0
+	if sp.file != nil {
+		_ = sp.file.Truncate(0)
+		_, _ = sp.file.Seek(0, io.SeekStart)
+	}
+}
+
+// close closes and removes the spool file.
+func (sp *spool) close() error {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	if sp.file == nil {
+		return nil
+	}
+	err := sp.file.Close()
+	_ = os.Remove(sp.file.Name())
+	sp.file = nil
+	sp.chunks = make(map[int]spoolChunk)
+	sp.size = 0
+	return err
+}
@@ -0,0 +1,105 @@
+package oviewer
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSpool_write(t *testing.T) {
+	sp := newSpool()
+	defer sp.close()
+	chunks := [][][]byte{
+		{[]byte("a\n"), []byte("b\n")},
+		{[]byte("c\n"), []byte("d\f")},
+	}
+	for i, lines := range chunks {
+		if err := sp.write(i, lines); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The chunk is written only once.
+	if err := sp.write(0, [][]byte{[]byte("x\n")}); err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []string{"a\nb\n", "c\nd\f\n"} {
+		r, err := sp.reader(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("reader(%d) = %q, want %q", i, got, want)
+		}
+	}
+	if _, err := sp.reader(2); err == nil {
+		t.Errorf("reader(2) should be error")
+	}
+	sp.reset()
+	if _, err := sp.reader(0); err == nil {
+		t.Errorf("reader(0) after reset should be error")
+	}
+}
+
+func TestDocument_loadReadSpool(t *testing.T) {
+	defer func(limit int) {
+		MemoryLimit = limit
+	}(MemoryLimit)
+	MemoryLimit = 2
+
+	contents := numberLines(55000)
+	m, err := NewDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.seekable = false
+	m.store.setNewLoadChunks(loadChunksCapacity(false))
+	m.spool = newSpool()
+	defer m.spool.close()
+
+	reader := bufio.NewReader(strings.NewReader(contents))
+	if reader, err = m.firstRead(reader); err != nil {
+		t.Fatal(err)
+	}
+	// Read to the end, spilling the old chunks.
+	for !m.BufEOF() {
+		m.store.evictChunksReload(m.spool)
+		if reader, err = m.continueRead(reader); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if m.BufEndNum() != 55000 {
+		t.Fatalf("BufEndNum() = %d, want 55000", m.BufEndNum())
+	}
+	if m.BufStartNum() != 0 {
+		t.Errorf("BufStartNum() = %d, want 0", m.BufStartNum())
+	}
+	if !m.store.isUnloadedChunk(1) {
+		t.Fatal("chunk 1 is not evicted")
+	}
+
+	lines := bytes.SplitAfter([]byte(contents), []byte("\n"))
+	for _, chunkNum := range []int{0, 1, 3, 2, 1} {
+		if _, err := m.loadRead(reader, chunkNum); err != nil {
+			t.Fatal(err)
+		}
+		for _, cn := range []int{0, 1, ChunkSize - 1} {
+			n := chunkNum*ChunkSize + cn
+			got, err := m.store.GetChunkLine(chunkNum, cn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := bytes.TrimSuffix(lines[n], []byte("\n")); !bytes.Equal(got, want) {
+				t.Errorf("line %d = %q, want %q", n, got, want)
+			}
+		}
+	}
+	if m.store.loadedChunks.Len() > MemoryLimit {
+		t.Errorf("loadedChunks = %d, want <= %d", m.store.loadedChunks.Len(), MemoryLimit)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"runtime/debug"
 	"sync/atomic"
 	"time"
 
@@ -118,10 +119,47 @@ func (s *store) evictChunksMem(chunkNum int) {
 	atomic.StoreInt32(&s.startNum, int32((k+1)*ChunkSize))
 }
 
+// evictChunksReload evicts the oldest chunk except the last chunk being read.
+// Unlike evictChunksMem, startNum is not changed because the chunk can be reloaded.
+// If sp is not nil, the chunk is written to the spool before being evicted.
+func (s *store) evictChunksReload(sp *spool) {
+	if MemoryLimit < 0 {
+		return
+	}
+	if s.loadedChunks.Len() < MemoryLimit {
+		return
+	}
+	last := s.lastChunkNum()
+	for _, k := range s.loadedChunks.Keys() {
+		if k == last {
+			continue
+		}
+		if sp != nil {
+			if err := sp.write(k, s.chunks[k].lines); err != nil {
+				log.Println(err)
+				return
+			}
+		}
+		s.unloadChunk(k)
+		return
+	}
+}
+
+// isUnloadedChunk returns true if the chunk has been read and unloaded from memory.
+func (s *store) isUnloadedChunk(chunkNum int) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if chunkNum == 0 || chunkNum >= len(s.chunks)-1 {
+		return false
+	}
+	return len(s.chunks[chunkNum].lines) == 0
+}
+
 // unloadChunk unloads the chunk from memory.
 func (s *store) unloadChunk(chunkNum int) {
 	s.loadedChunks.Remove(chunkNum)
 	s.chunks[chunkNum].lines = nil
+	s.chunks[chunkNum].mapped = false
 }
 
 // lastChunkNum returns the last chunk number.
@@ -227,6 +265,95 @@ func (s *store) readLines(chunk *chunk, reader *bufio.Reader, start int, end int
 	return nil
 }
 
+// mapLines fills the lines from start to end in chunk with slices of the mapped file
+// instead of copying them.
+// It returns false if the lines of the chunk are not in the mapping,
+// or the file is truncated while the lines are being found.
+func (s *store) mapLines(chunk *chunk, data []byte, start int, end int) (ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer debug.SetPanicOnFault(debug.SetPanicOnFault(true))
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("mmap: %s: %v", ErrTruncated, r)
+			ok = false
+		}
+	}()
+
+	// Only the counted part of the file is used.
+	if s.size < int64(len(data)) {
+		data = data[:s.size]
+	}
+	pos := chunk.start
+	if pos > int64(len(data)) {
+		return false
+	}
+	lines := make([][]byte, 0, end-start)
+	for num := start; num < end; num++ {
+		p := bytes.IndexByte(data[pos:], '\n')
+		if p < 0 {
+			// The last line without a newline.
+			if pos < int64(len(data)) && s.size == int64(len(data)) {
+				lines = append(lines, data[pos:len(data):len(data)])
+				break
+			}
+			return false
+		}
+		next := pos + int64(p) + 1
+		lines = append(lines, data[pos:next:next])
+		pos = next
+	}
+	chunk.lines = lines
+	chunk.mapped = true
+	return true
+}
+
+// copyMapped returns a copy of the line of the mapped file.
+// Reading the part of the mapping beyond the end of the truncated file causes SIGBUS,
+// so the fault is returned as ErrTruncated instead of crashing.
+func copyMapped(line []byte) (b []byte, err error) {
+	defer debug.SetPanicOnFault(debug.SetPanicOnFault(true))
+	defer func() {
+		if r := recover(); r != nil {
+			b, err = nil, fmt.Errorf("%w: %v", ErrTruncated, r)
+		}
+	}()
+	return append([]byte(nil), line...), nil
+}
+
+// detachMapped detaches the lines of the chunks from the mapped file so that it can be unmapped.
+// The lines are copied if keep is true, otherwise the chunks are unloaded.
+// The caller must hold s.mu.
+func (s *store) detachMapped(keep bool) {
+	for chunkNum, chunk := range s.chunks {
+		if !chunk.mapped {
+			continue
+		}
+		chunk.mapped = false
+		if !keep {
+			if s.loadedChunks != nil {
+				s.loadedChunks.Remove(chunkNum)
+			}
+			chunk.lines = nil
+			continue
+		}
+		lines := make([][]byte, len(chunk.lines), cap(chunk.lines))
+		for n, line := range chunk.lines {
+			b, err := copyMapped(line)
+			if err != nil {
+				log.Printf("mmap: %s", err)
+				lines = nil
+				break
+			}
+			lines[n] = b
+		}
+		if lines == nil && s.loadedChunks != nil {
+			s.loadedChunks.Remove(chunkNum)
+		}
+		chunk.lines = lines
+	}
+}
+
 // countLines counts the number of lines and the size of the buffer.
 func (s *store) countLines(reader *bufio.Reader, start int, end int) (int, int, error) {
 	count := 0
@@ -371,7 +498,15 @@ func (s *store) export(w io.Writer, chunk *chunk, start int, end int) error {
 		end = len(chunk.lines)
 	}
 	for i := start; i < end; i++ {
-		if _, err := w.Write(chunk.lines[i]); err != nil {
+		line := chunk.lines[i]
+		if chunk.mapped {
+			b, err := copyMapped(line)
+			if err != nil {
+				return err
+			}
+			line = b
+		}
+		if _, err := w.Write(line); err != nil {
 			return err
 		}
 	}
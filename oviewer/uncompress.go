@@ -1,12 +1,18 @@
 package oviewer
 
 import (
+	"bufio"
 	"bytes"
 	"compress/bzip2"
 	"compress/gzip"
+	"compress/zlib"
 	"errors"
 	"io"
+	"path/filepath"
+	"strings"
 
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/s2"
 	"github.com/klauspost/compress/zstd"
 	"github.com/pierrec/lz4"
 	"github.com/ulikunitz/xz"
@@ -28,6 +34,13 @@ const (
 	LZ4
 	// XZ is xz compressed format.
 	XZ
+	// BROTLI is brotli compressed format.
+	// Brotli has no magic number, so it is detected by the file extension.
+	BROTLI
+	// SNAPPY is snappy framed format.
+	SNAPPY
+	// ZLIB is zlib compressed format.
+	ZLIB
 )
 
 func compressType(header []byte) Compressed {
@@ -42,6 +55,33 @@ func compressType(header []byte) Compressed {
 		return LZ4
 	case bytes.Equal(header[:7], []byte{0xfd, 0x37, 0x7a, 0x58, 0x5a, 0x0, 0x0}):
 		return XZ
+	case bytes.Equal(header[:7], []byte{0xff, 0x06, 0x00, 0x00, 0x73, 0x4e, 0x61}):
+		return SNAPPY
+	case isZlibHeader(header[:2]):
+		return ZLIB
+	}
+	return UNCOMPRESSED
+}
+
+// isZlibHeader returns true if the header is a zlib header with the default window size.
+// Only the common compression levels are accepted to avoid misdetecting text starting with 'x'.
+func isZlibHeader(header []byte) bool {
+	if header[0] != 0x78 {
+		return false
+	}
+	switch header[1] {
+	case 0x01, 0x5e, 0x9c, 0xda:
+		return true
+	}
+	return false
+}
+
+// compressTypeByName returns the compressed format that is determined by the file extension.
+// It is used for formats without a magic number.
+func compressTypeByName(fileName string) Compressed {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".br":
+		return BROTLI
 	}
 	return UNCOMPRESSED
 }
@@ -59,12 +99,22 @@ func (c Compressed) String() string {
 		return "LZ4"
 	case XZ:
 		return "XZ"
+	case BROTLI:
+		return "BROTLI"
+	case SNAPPY:
+		return "SNAPPY"
+	case ZLIB:
+		return "ZLIB"
 	}
 	return "UNCOMPRESSED"
 }
 
 // uncompressedReader returns a reader for the uncompressed format.
-func uncompressedReader(reader io.Reader, seekable bool) (Compressed, io.Reader) {
+// The fileName is used to detect the format without a magic number.
+func uncompressedReader(fileName string, reader io.Reader, seekable bool) (Compressed, io.Reader) {
+	if cFormat := compressTypeByName(fileName); cFormat != UNCOMPRESSED {
+		return cFormat, compressedFormatReader(cFormat, reader)
+	}
 	buf := [7]byte{}
 	n, err := io.ReadAtLeast(reader, buf[:], len(buf))
 	if err != nil {
@@ -75,15 +125,37 @@ func uncompressedReader(reader io.Reader, seekable bool) (Compressed, io.Reader)
 	}
 
 	cFormat := compressType(buf[:7])
+	var mr io.Reader = io.MultiReader(bytes.NewReader(buf[:n]), reader)
+	if cFormat == ZLIB {
+		br := bufio.NewReader(mr)
+		if !isZlibStream(br) {
+			cFormat = UNCOMPRESSED
+		}
+		mr = br
+	}
 	if seekable && cFormat == UNCOMPRESSED {
 		return UNCOMPRESSED, nil
 	}
 
-	mr := io.MultiReader(bytes.NewReader(buf[:n]), reader)
 	r := compressedFormatReader(cFormat, mr)
 	return cFormat, r
 }
 
+// zlibCheckSize is the size to check if it is a zlib stream.
+const zlibCheckSize = 512
+
+// isZlibStream returns true if the beginning of the reader can be decompressed as zlib.
+// The zlib header is only two bytes, so the header alone can match a text file.
+func isZlibStream(br *bufio.Reader) bool {
+	p, _ := br.Peek(zlibCheckSize)
+	zr, err := zlib.NewReader(bytes.NewReader(p))
+	if err != nil {
+		return false
+	}
+	_, err = io.Copy(io.Discard, zr)
+	return err == nil || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
 // compressedFormatReader returns a reader for the compressed format.
 func compressedFormatReader(cFormat Compressed, reader io.Reader) io.Reader {
 	var r io.Reader
@@ -99,6 +171,12 @@ func compressedFormatReader(cFormat Compressed, reader io.Reader) io.Reader {
 		r = lz4.NewReader(reader)
 	case XZ:
 		r, err = xz.NewReader(reader)
+	case BROTLI:
+		r = brotli.NewReader(reader)
+	case SNAPPY:
+		r = s2.NewReader(reader)
+	case ZLIB:
+		r, err = zlib.NewReader(reader)
 	}
 	if err != nil || r == nil {
 		r = reader
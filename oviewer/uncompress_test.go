@@ -0,0 +1,135 @@
+package oviewer
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gdamore/tcell/v2"
+	"github.com/klauspost/compress/s2"
+)
+
+func compressData(t *testing.T, cFormat Compressed, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch cFormat {
+	case BROTLI:
+		w = brotli.NewWriter(&buf)
+	case SNAPPY:
+		w = s2.NewWriter(&buf, s2.WriterSnappyCompat())
+	case ZLIB:
+		w = zlib.NewWriter(&buf)
+	default:
+		return data
+	}
+	if _, err := w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func Test_uncompressedReader(t *testing.T) {
+	data := []byte("test\ndata\n")
+	tests := []struct {
+		name     string
+		fileName string
+		src      []byte
+		cFormat  Compressed
+		want     Compressed
+	}{
+		{
+			name:     "testBrotli",
+			fileName: "test.txt.br",
+			cFormat:  BROTLI,
+			want:     BROTLI,
+		},
+		{
+			name:     "testSnappy",
+			fileName: "test.sz",
+			cFormat:  SNAPPY,
+			want:     SNAPPY,
+		},
+		{
+			name:     "testZlib",
+			fileName: "test.zz",
+			cFormat:  ZLIB,
+			want:     ZLIB,
+		},
+		{
+			name:     "testUncompressed",
+			fileName: "test.txt",
+			cFormat:  UNCOMPRESSED,
+			want:     UNCOMPRESSED,
+		},
+		{
+			name:     "testTextStartsWithX",
+			fileName: "test.txt",
+			src:      []byte("x^test\ndata\n"),
+			cFormat:  UNCOMPRESSED,
+			want:     UNCOMPRESSED,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := data
+			if tt.src != nil {
+				src = tt.src
+			}
+			cFormat, r := uncompressedReader(tt.fileName, bytes.NewReader(compressData(t, tt.cFormat, src)), false)
+			if cFormat != tt.want {
+				t.Errorf("uncompressedReader() cFormat = %v, want %v", cFormat, tt.want)
+			}
+			got, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, src) {
+				t.Errorf("uncompressedReader() = %q, want %q", got, src)
+			}
+		})
+	}
+}
+
+func TestRoot_normalLeftStatusFormat(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	tests := []struct {
+		name         string
+		showFilename bool
+		want         string
+	}{
+		{
+			name:         "testShowFilename",
+			showFilename: true,
+			want:         "test.gz(GZIP):",
+		},
+		{
+			name:         "testHideFilename",
+			showFilename: false,
+			want:         ":",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := NewRoot(bytes.NewBufferString("test"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			root.Doc.FileName = "test.gz"
+			root.Doc.CFormat = GZIP
+			root.Config.Prompt.Normal.ShowFilename = tt.showFilename
+			lc, _ := root.normalLeftStatus()
+			if got, _ := ContentsToStr(lc); got != tt.want {
+				t.Errorf("normalLeftStatus() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@ package oviewer
 import (
 	"regexp"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"golang.org/x/exp/constraints"
 )
@@ -84,6 +86,56 @@ func allIndex(s string, substr string, reg *regexp.Regexp) [][]int {
 	return allStringIndex(s, substr)
 }
 
+// allStringIndexFold returns all matching string positions ignoring case.
+// substr must be lowercase.
+// The positions are in s even if the lowercase of s has a different length.
+func allStringIndexFold(s string, substr string) [][]int {
+	if isASCII(s) {
+		return allStringIndex(strings.ToLower(s), substr)
+	}
+	if len(substr) == 0 {
+		return nil
+	}
+	var result [][]int
+	for pos := 0; pos < len(s); {
+		if n := hasPrefixFold(s[pos:], substr); n > 0 {
+			result = append(result, []int{pos, pos + n})
+			pos += n
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(s[pos:])
+		pos += size
+	}
+	return result
+}
+
+// hasPrefixFold returns the length of the prefix of s that matches lowercase substr.
+// It returns 0 if s does not begin with substr.
+func hasPrefixFold(s string, substr string) int {
+	n := 0
+	for _, p := range substr {
+		if n >= len(s) {
+			return 0
+		}
+		r, size := utf8.DecodeRuneInString(s[n:])
+		if unicode.ToLower(r) != p {
+			return 0
+		}
+		n += size
+	}
+	return n
+}
+
+// isASCII returns true if s contains only ASCII characters.
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
 // allStringIndex returns all matching string positions.
 func allStringIndex(s string, substr string) [][]int {
 	if len(substr) == 0 {
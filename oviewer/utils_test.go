@@ -531,3 +531,48 @@ func Test_allStringIndex(t *testing.T) {
 		})
 	}
 }
+
+func Test_allStringIndexFold(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		s      string
+		substr string
+		want   [][]int
+	}{
+		{
+			name:   "testASCII",
+			s:      "Error: error",
+			substr: "error",
+			want:   [][]int{{0, 5}, {7, 12}},
+		},
+		{
+			name:   "testMultiByte",
+			s:      "あERROR",
+			substr: "error",
+			want:   [][]int{{3, 8}},
+		},
+		{
+			// The lowercase of "İ" has a different length.
+			name:   "testLengthChange",
+			s:      "İ Error",
+			substr: "error",
+			want:   [][]int{{3, 8}},
+		},
+		{
+			name:   "testNone",
+			s:      "あいう",
+			substr: "error",
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := allStringIndexFold(tt.s, tt.substr); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("allStringIndexFold() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}